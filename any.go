@@ -0,0 +1,73 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// anyMessage is satisfied by both the golang and the gogo flavours of google.protobuf.Any.
+type anyMessage interface {
+	GetTypeUrl() string
+	GetValue() []byte
+}
+
+// CallAnyValidatorIfExists unpacks the message held by a google.protobuf.Any and calls its Validate method.
+func CallAnyValidatorIfExists(candidate anyMessage) error {
+	msg, err := unpackAny(candidate, nil)
+	if err != nil {
+		return err
+	}
+	return CallValidatorIfExists(msg)
+}
+
+// CallAnyValidatorsIfExists unpacks the message held by a google.protobuf.Any and calls its ValidateAll method.
+func CallAnyValidatorsIfExists(candidate anyMessage) error {
+	msg, err := unpackAny(candidate, nil)
+	if err != nil {
+		return err
+	}
+	return CallValidatorsIfExists(msg)
+}
+
+// CallContextAnyValidatorsIfExists unpacks the message held by a google.protobuf.Any with the AnyResolver of the
+// Options of ctx and validates all its fields with CallContextValidatorsIfExists.
+func CallContextAnyValidatorsIfExists(ctx context.Context, candidate anyMessage) error {
+	msg, err := unpackAny(candidate, OptionsFromContext(ctx).AnyResolver)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return nil
+	}
+	if MaskFromContext(ctx) != nil {
+		// masks don't go through google.protobuf.Any, the message it holds is validated as a whole
+		ctx = context.WithValue(ctx, maskKey{}, ValidationMask(nil))
+	}
+	return CallContextValidatorsIfExists(ctx, msg)
+}
+
+// unpackAny returns the message held by candidate, looking its type up with resolver or the global registry when nil.
+func unpackAny(candidate anyMessage, resolver protoregistry.MessageTypeResolver) (proto.Message, error) {
+	typeURL := candidate.GetTypeUrl()
+	if typeURL == "" {
+		return nil, nil
+	}
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	mt, err := resolver.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve type '%s': %v", typeURL, err)
+	}
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(candidate.GetValue(), msg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal type '%s': %v", typeURL, err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CELRule is a CEL expression set with the cel option of validator.field or validator.message.
+type CELRule struct {
+	// Field is the Go name of the field the rule is set on, empty for message rules.
+	Field string
+	// ProtoName is the name of the field in the message descriptor, empty for message rules.
+	ProtoName string
+	// Id identifies the violation reported when the expression does not hold.
+	Id string
+	// Message describes the violation reported when the expression does not hold.
+	Message string
+	// Expression is the CEL expression, `this` being the field value or the message.
+	Expression string
+	// Groups are the validation groups the rule belongs to.
+	Groups []string
+	// Warning reports the violations of the rule as warnings, which don't fail the validation.
+	Warning bool
+}
+
+func (r *CELRule) violation() string {
+	if r.Id != "" {
+		return r.Id
+	}
+	return "cel"
+}
+
+func (r *CELRule) errorMsg() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return fmt.Sprintf("value must satisfy the expression '%s'", r.Expression)
+}
+
+// CELValidator evaluates the CEL rules of a message type. The rules are compiled once, the first time a message is
+// validated.
+type CELValidator struct {
+	rules    []*CELRule
+	once     sync.Once
+	programs []cel.Program
+	err      error
+}
+
+// NewCELValidator is used by the generated code to declare the CEL rules of a message type.
+func NewCELValidator(rules ...*CELRule) *CELValidator {
+	return &CELValidator{rules: rules}
+}
+
+// Validate returns the first CEL rule of the default group violated by msg.
+func (v *CELValidator) Validate(msg interface{}) error {
+	return v.ValidateGroups(msg, nil)
+}
+
+// ValidateGroups returns the first CEL rule of groups violated by msg.
+func (v *CELValidator) ValidateGroups(msg interface{}, groups []string) error {
+	m, err := v.message(msg)
+	if err != nil {
+		return err
+	}
+	for i, rule := range v.rules {
+		if rule.Warning || !InGroups(groups, rule.Groups...) {
+			continue
+		}
+		if err := v.eval(i, m); err != nil {
+			if rule.Field == "" {
+				return err
+			}
+			return FieldError(rule.Field, err)
+		}
+	}
+	return nil
+}
+
+// ValidateAll adds every CEL rule of the default group violated by msg to validations.
+func (v *CELValidator) ValidateAll(msg interface{}, validations *ValidationErrors) {
+	v.ValidateAllGroups(msg, validations, nil)
+}
+
+// ValidateAllGroups adds every CEL rule of groups violated by msg to validations.
+func (v *CELValidator) ValidateAllGroups(msg interface{}, validations *ValidationErrors, groups []string) {
+	v.validateAllGroups(msg, validations, groups, nil)
+}
+
+// ValidateAllMask adds every CEL rule of groups violated by msg to validations, restricted to the rules of the fields
+// listed in mask. The rules of the message are only checked when mask is nil.
+func (v *CELValidator) ValidateAllMask(msg interface{}, validations *ValidationErrors, groups []string, mask ValidationMask) {
+	if mask == nil {
+		v.validateAllGroups(msg, validations, groups, nil)
+		return
+	}
+	v.validateAllGroups(msg, validations, groups, func(rule *CELRule) bool {
+		return rule.ProtoName != "" && mask.Lists(rule.ProtoName)
+	})
+}
+
+// validateAllGroups adds every CEL rule of groups violated by msg to validations, skipping the rules rejected by keep.
+func (v *CELValidator) validateAllGroups(msg interface{}, validations *ValidationErrors, groups []string, keep func(*CELRule) bool) {
+	m, err := v.message(msg)
+	if err != nil {
+		validations.AddValidationError("", "cel", err)
+		return
+	}
+	for i, rule := range v.rules {
+		if !InGroups(groups, rule.Groups...) || (keep != nil && !keep(rule)) {
+			continue
+		}
+		if err := v.eval(i, m); err != nil && rule.Warning {
+			validations.AddValidationWarning(rule.Field, rule.violation(), err)
+		} else if err != nil {
+			validations.AddValidationError(rule.Field, rule.violation(), err)
+		}
+	}
+}
+
+func (v *CELValidator) message(msg interface{}) (protoreflect.Message, error) {
+	var m protoreflect.Message
+	switch t := msg.(type) {
+	case proto.Message:
+		m = t.ProtoReflect()
+	case protoadapt.MessageV1:
+		m = protoadapt.MessageV2Of(t).ProtoReflect()
+	default:
+		return nil, fmt.Errorf("unable to evaluate CEL rules on %T", msg)
+	}
+	v.once.Do(func() {
+		v.programs = make([]cel.Program, len(v.rules))
+		for i, rule := range v.rules {
+			if v.programs[i], v.err = CompileCELRule(m.Descriptor(), rule); v.err != nil {
+				return
+			}
+		}
+	})
+	return m, v.err
+}
+
+func (v *CELValidator) eval(i int, m protoreflect.Message) error {
+	rule := v.rules[i]
+	var this interface{} = m.Interface()
+	if rule.ProtoName != "" {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(rule.ProtoName))
+		this = m.Get(fd)
+		if fd.IsMap() {
+			this = mapInterface(m.Get(fd).Map())
+		}
+	}
+	out, _, err := v.programs[i].Eval(map[string]interface{}{"this": this})
+	if err != nil {
+		return fmt.Errorf("unable to evaluate the expression '%s': %v", rule.Expression, err)
+	}
+	if ok, isBool := out.Value().(bool); !isBool || !ok {
+		return errors.New(rule.errorMsg())
+	}
+	return nil
+}
+
+func mapInterface(m protoreflect.Map) map[interface{}]interface{} {
+	values := make(map[interface{}]interface{}, m.Len())
+	m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		values[k.Interface()] = v.Interface()
+		return true
+	})
+	return values
+}
+
+// CompileCELRule parses and type-checks a CEL rule against the descriptor of the message it is set on.
+func CompileCELRule(md protoreflect.MessageDescriptor, rule *CELRule) (cel.Program, error) {
+	thisType := cel.ObjectType(string(md.FullName()))
+	if rule.ProtoName != "" {
+		fd := md.Fields().ByName(protoreflect.Name(rule.ProtoName))
+		if fd == nil {
+			return nil, fmt.Errorf("field %s does not exist in %s", rule.ProtoName, md.FullName())
+		}
+		thisType = celFieldType(fd)
+	}
+	env, err := cel.NewEnv(
+		cel.Container(string(md.ParentFile().Package())),
+		cel.TypeDescs(celFileDescs(md.ParentFile(), nil)...),
+		cel.Variable("this", thisType),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(rule.Expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %v", rule.Expression, issues.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression '%s' must evaluate to a bool, not %v", rule.Expression, ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// celFileDescs lists a file and all of its transitive imports.
+func celFileDescs(fd protoreflect.FileDescriptor, seen map[string]bool) []interface{} {
+	if seen == nil {
+		seen = map[string]bool{}
+	}
+	if seen[fd.Path()] {
+		return nil
+	}
+	seen[fd.Path()] = true
+	descs := []interface{}{fd}
+	for i := 0; i < fd.Imports().Len(); i++ {
+		descs = append(descs, celFileDescs(fd.Imports().Get(i).FileDescriptor, seen)...)
+	}
+	return descs
+}
+
+func celFieldType(fd protoreflect.FieldDescriptor) *cel.Type {
+	switch {
+	case fd.IsMap():
+		return cel.MapType(celKindType(fd.MapKey()), celKindType(fd.MapValue()))
+	case fd.IsList():
+		return cel.ListType(celKindType(fd))
+	}
+	return celKindType(fd)
+}
+
+func celKindType(fd protoreflect.FieldDescriptor) *cel.Type {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return cel.BoolType
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return cel.IntType
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return cel.UintType
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return cel.DoubleType
+	case protoreflect.StringKind:
+		return cel.StringType
+	case protoreflect.BytesKind:
+		return cel.BytesType
+	}
+	switch fd.Message().FullName() {
+	case "google.protobuf.Timestamp":
+		return cel.TimestampType
+	case "google.protobuf.Duration":
+		return cel.DurationType
+	case "google.protobuf.Any":
+		return cel.AnyType
+	}
+	return cel.ObjectType(string(fd.Message().FullName()))
+}
@@ -8,7 +8,9 @@ import (
 	math "math"
 	proto "github.com/golang/protobuf/proto"
 	_ "github.com/monstrum/go-proto-validators"
+	context "context"
 	github_com_monstrum_go_proto_validators "github.com/monstrum/go-proto-validators"
+	google_golang_org_protobuf_types_known_fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -17,8 +19,60 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 func (this *SomeMsg) Validate() error {
-	if _, ok := Action_name[int32(this.Do)]; !ok {
-		return github_com_monstrum_go_proto_validators.FieldError("Do", fmt.Errorf(`value '%v' must be a valid Action field`, this.Do))
+	return this.ValidateGroups()
+}
+func (this *SomeMsg) ValidateGroups(groups ...string) error {
+	return github_com_monstrum_go_proto_validators.ValidateFirst(this, groups...)
+}
+func (this *SomeMsg) ValidateAll() error {
+	return this.ValidateAllWithWarnings().Err()
+}
+func (this *SomeMsg) ValidateAllWithWarnings() *github_com_monstrum_go_proto_validators.ValidationErrors {
+	validations, _ := this.ValidateAllContextWithWarnings(context.Background())
+	return validations
+}
+func (this *SomeMsg) ValidateAllGroups(groups ...string) error {
+	return this.ValidateAllContext(github_com_monstrum_go_proto_validators.WithOptions(context.Background(), github_com_monstrum_go_proto_validators.Groups(groups...)))
+}
+func (this *SomeMsg) ValidateContext(ctx context.Context, opts ...github_com_monstrum_go_proto_validators.Option) error {
+	return github_com_monstrum_go_proto_validators.ValidateContext(ctx, this, opts...)
+}
+func (this *SomeMsg) ValidateAllContext(ctx context.Context) error {
+	validations, err := this.ValidateAllContextWithWarnings(ctx)
+	if err != nil {
+		return err
+	}
+	return validations.Err()
+}
+func (this *SomeMsg) ValidateAllContextWithWarnings(ctx context.Context) (*github_com_monstrum_go_proto_validators.ValidationErrors, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	validations := github_com_monstrum_go_proto_validators.NewValidationErrors(ctx)
+	groups := github_com_monstrum_go_proto_validators.OptionsFromContext(ctx).Groups
+	mask := github_com_monstrum_go_proto_validators.MaskFromContext(ctx)
+	if mask.Lists("do") {
+		if github_com_monstrum_go_proto_validators.InGroups(groups) {
+			if _, ok := Action_name[int32(this.Do)]; !ok {
+				validations.AddValidationError("Do", "is_in_enum", fmt.Sprintf(`value '%v' must be a valid Action field`, this.Do))
+			}
+		}
 	}
-	return nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return validations.Complete(), nil
+}
+func (this *SomeMsg) ValidateMask(mask *google_golang_org_protobuf_types_known_fieldmaskpb.FieldMask) error {
+	ctx, err := github_com_monstrum_go_proto_validators.WithMask(context.Background(), this, mask.GetPaths())
+	if err != nil {
+		return err
+	}
+	return this.ValidateAllContext(ctx)
+}
+func (this *SomeMsg) ValidationRules() *github_com_monstrum_go_proto_validators.Rules {
+	return github_com_monstrum_go_proto_validators.MessageRules(this)
+}
+func init() {
+	github_com_monstrum_go_proto_validators.RegisterValidator("validator.examples.SomeMsg", func() interface{} { return &SomeMsg{} })
 }
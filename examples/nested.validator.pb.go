@@ -9,7 +9,9 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	_ "github.com/monstrum/go-proto-validators"
 	regexp "regexp"
+	context "context"
 	github_com_monstrum_go_proto_validators "github.com/monstrum/go-proto-validators"
+	google_golang_org_protobuf_types_known_fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -18,28 +20,134 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 func (this *InnerMessage) Validate() error {
-	if !(this.SomeInteger > 0) {
-		return github_com_monstrum_go_proto_validators.FieldError("SomeInteger", fmt.Errorf(`value '%v' must be greater than '0'`, this.SomeInteger))
+	return this.ValidateGroups()
+}
+func (this *InnerMessage) ValidateGroups(groups ...string) error {
+	return github_com_monstrum_go_proto_validators.ValidateFirst(this, groups...)
+}
+func (this *InnerMessage) ValidateAll() error {
+	return this.ValidateAllWithWarnings().Err()
+}
+func (this *InnerMessage) ValidateAllWithWarnings() *github_com_monstrum_go_proto_validators.ValidationErrors {
+	validations, _ := this.ValidateAllContextWithWarnings(context.Background())
+	return validations
+}
+func (this *InnerMessage) ValidateAllGroups(groups ...string) error {
+	return this.ValidateAllContext(github_com_monstrum_go_proto_validators.WithOptions(context.Background(), github_com_monstrum_go_proto_validators.Groups(groups...)))
+}
+func (this *InnerMessage) ValidateContext(ctx context.Context, opts ...github_com_monstrum_go_proto_validators.Option) error {
+	return github_com_monstrum_go_proto_validators.ValidateContext(ctx, this, opts...)
+}
+func (this *InnerMessage) ValidateAllContext(ctx context.Context) error {
+	validations, err := this.ValidateAllContextWithWarnings(ctx)
+	if err != nil {
+		return err
+	}
+	return validations.Err()
+}
+func (this *InnerMessage) ValidateAllContextWithWarnings(ctx context.Context) (*github_com_monstrum_go_proto_validators.ValidationErrors, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	validations := github_com_monstrum_go_proto_validators.NewValidationErrors(ctx)
+	groups := github_com_monstrum_go_proto_validators.OptionsFromContext(ctx).Groups
+	mask := github_com_monstrum_go_proto_validators.MaskFromContext(ctx)
+	if mask.Lists("some_integer") {
+		if github_com_monstrum_go_proto_validators.InGroups(groups) {
+			if !(this.SomeInteger > 0) {
+				validations.AddValidationError("SomeInteger", "int_gt", fmt.Sprintf(`value '%v' must be greater than '0'`, this.SomeInteger))
+			}
+			if !(this.SomeInteger < 100) {
+				validations.AddValidationError("SomeInteger", "int_lt", fmt.Sprintf(`value '%v' must be less than '100'`, this.SomeInteger))
+			}
+		}
 	}
-	if !(this.SomeInteger < 100) {
-		return github_com_monstrum_go_proto_validators.FieldError("SomeInteger", fmt.Errorf(`value '%v' must be less than '100'`, this.SomeInteger))
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	return validations.Complete(), nil
+}
+func (this *InnerMessage) ValidateMask(mask *google_golang_org_protobuf_types_known_fieldmaskpb.FieldMask) error {
+	ctx, err := github_com_monstrum_go_proto_validators.WithMask(context.Background(), this, mask.GetPaths())
+	if err != nil {
+		return err
+	}
+	return this.ValidateAllContext(ctx)
+}
+func (this *InnerMessage) ValidationRules() *github_com_monstrum_go_proto_validators.Rules {
+	return github_com_monstrum_go_proto_validators.MessageRules(this)
 }
 
 var _regex_OuterMessage_ImportantString = regexp.MustCompile(`^[a-z]{2,5}$`)
 
 func (this *OuterMessage) Validate() error {
-	if !_regex_OuterMessage_ImportantString.MatchString(this.ImportantString) {
-		return github_com_monstrum_go_proto_validators.FieldError("ImportantString", fmt.Errorf(`value '%v' must be a string conforming to regex "^[a-z]{2,5}$"`, this.ImportantString))
+	return this.ValidateGroups()
+}
+func (this *OuterMessage) ValidateGroups(groups ...string) error {
+	return github_com_monstrum_go_proto_validators.ValidateFirst(this, groups...)
+}
+func (this *OuterMessage) ValidateAll() error {
+	return this.ValidateAllWithWarnings().Err()
+}
+func (this *OuterMessage) ValidateAllWithWarnings() *github_com_monstrum_go_proto_validators.ValidationErrors {
+	validations, _ := this.ValidateAllContextWithWarnings(context.Background())
+	return validations
+}
+func (this *OuterMessage) ValidateAllGroups(groups ...string) error {
+	return this.ValidateAllContext(github_com_monstrum_go_proto_validators.WithOptions(context.Background(), github_com_monstrum_go_proto_validators.Groups(groups...)))
+}
+func (this *OuterMessage) ValidateContext(ctx context.Context, opts ...github_com_monstrum_go_proto_validators.Option) error {
+	return github_com_monstrum_go_proto_validators.ValidateContext(ctx, this, opts...)
+}
+func (this *OuterMessage) ValidateAllContext(ctx context.Context) error {
+	validations, err := this.ValidateAllContextWithWarnings(ctx)
+	if err != nil {
+		return err
+	}
+	return validations.Err()
+}
+func (this *OuterMessage) ValidateAllContextWithWarnings(ctx context.Context) (*github_com_monstrum_go_proto_validators.ValidationErrors, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	if nil == this.Inner {
-		return github_com_monstrum_go_proto_validators.FieldError("Inner", fmt.Errorf("message must exist"))
+	validations := github_com_monstrum_go_proto_validators.NewValidationErrors(ctx)
+	groups := github_com_monstrum_go_proto_validators.OptionsFromContext(ctx).Groups
+	mask := github_com_monstrum_go_proto_validators.MaskFromContext(ctx)
+	if mask.Lists("important_string") {
+		if github_com_monstrum_go_proto_validators.InGroups(groups) {
+			if !_regex_OuterMessage_ImportantString.MatchString(this.ImportantString) {
+				validations.AddValidationError("ImportantString", "regex", fmt.Sprintf(`value '%v' must be a string conforming to regex "^[a-z]{2,5}$"`, this.ImportantString))
+			}
+		}
 	}
-	if this.Inner != nil {
-		if err := github_com_monstrum_go_proto_validators.CallValidatorIfExists(this.Inner); err != nil {
-			return github_com_monstrum_go_proto_validators.FieldError("Inner", err)
+	if mask.Lists("inner") {
+		if github_com_monstrum_go_proto_validators.InGroups(groups) {
+			if nil == this.Inner {
+				validations.AddValidationError("Inner", "empty", "message must exist")
+			}
+		}
+		if this.Inner != nil {
+			if err := github_com_monstrum_go_proto_validators.CallContextValidatorsIfExists(mask.Context(ctx, "inner"), this.Inner); err != nil {
+				validations.AddValidationError("Inner", "message", err)
+			}
 		}
 	}
-	return nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return validations.Complete(), nil
+}
+func (this *OuterMessage) ValidateMask(mask *google_golang_org_protobuf_types_known_fieldmaskpb.FieldMask) error {
+	ctx, err := github_com_monstrum_go_proto_validators.WithMask(context.Background(), this, mask.GetPaths())
+	if err != nil {
+		return err
+	}
+	return this.ValidateAllContext(ctx)
+}
+func (this *OuterMessage) ValidationRules() *github_com_monstrum_go_proto_validators.Rules {
+	return github_com_monstrum_go_proto_validators.MessageRules(this)
+}
+func init() {
+	github_com_monstrum_go_proto_validators.RegisterValidator("validator.examples.InnerMessage", func() interface{} { return &InnerMessage{} })
+	github_com_monstrum_go_proto_validators.RegisterValidator("validator.examples.OuterMessage", func() interface{} { return &OuterMessage{} })
 }
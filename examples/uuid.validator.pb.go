@@ -9,7 +9,9 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	_ "github.com/monstrum/go-proto-validators"
 	regexp "regexp"
+	context "context"
 	github_com_monstrum_go_proto_validators "github.com/monstrum/go-proto-validators"
+	google_golang_org_protobuf_types_known_fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -20,11 +22,63 @@ var _ = math.Inf
 var _regex_UUIDMsg_UserId = regexp.MustCompile(`^([a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[4][a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12})?$`)
 
 func (this *UUIDMsg) Validate() error {
-	if !_regex_UUIDMsg_UserId.MatchString(this.UserId) {
-		return github_com_monstrum_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be a string conforming to regex "^([a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[4][a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12})?$"`, this.UserId))
+	return this.ValidateGroups()
+}
+func (this *UUIDMsg) ValidateGroups(groups ...string) error {
+	return github_com_monstrum_go_proto_validators.ValidateFirst(this, groups...)
+}
+func (this *UUIDMsg) ValidateAll() error {
+	return this.ValidateAllWithWarnings().Err()
+}
+func (this *UUIDMsg) ValidateAllWithWarnings() *github_com_monstrum_go_proto_validators.ValidationErrors {
+	validations, _ := this.ValidateAllContextWithWarnings(context.Background())
+	return validations
+}
+func (this *UUIDMsg) ValidateAllGroups(groups ...string) error {
+	return this.ValidateAllContext(github_com_monstrum_go_proto_validators.WithOptions(context.Background(), github_com_monstrum_go_proto_validators.Groups(groups...)))
+}
+func (this *UUIDMsg) ValidateContext(ctx context.Context, opts ...github_com_monstrum_go_proto_validators.Option) error {
+	return github_com_monstrum_go_proto_validators.ValidateContext(ctx, this, opts...)
+}
+func (this *UUIDMsg) ValidateAllContext(ctx context.Context) error {
+	validations, err := this.ValidateAllContextWithWarnings(ctx)
+	if err != nil {
+		return err
 	}
-	if this.UserId == "" {
-		return github_com_monstrum_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must not be an empty string`, this.UserId))
+	return validations.Err()
+}
+func (this *UUIDMsg) ValidateAllContextWithWarnings(ctx context.Context) (*github_com_monstrum_go_proto_validators.ValidationErrors, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	validations := github_com_monstrum_go_proto_validators.NewValidationErrors(ctx)
+	groups := github_com_monstrum_go_proto_validators.OptionsFromContext(ctx).Groups
+	mask := github_com_monstrum_go_proto_validators.MaskFromContext(ctx)
+	if mask.Lists("user_id") {
+		if github_com_monstrum_go_proto_validators.InGroups(groups) {
+			if !_regex_UUIDMsg_UserId.MatchString(this.UserId) {
+				validations.AddValidationError("UserId", "regex", fmt.Sprintf(`value '%v' must be a string conforming to regex "^([a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[4][a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12})?$"`, this.UserId))
+			}
+			if this.UserId == "" {
+				validations.AddValidationError("UserId", "string_not_empty", fmt.Sprintf(`value '%v' must not be an empty string`, this.UserId))
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return validations.Complete(), nil
+}
+func (this *UUIDMsg) ValidateMask(mask *google_golang_org_protobuf_types_known_fieldmaskpb.FieldMask) error {
+	ctx, err := github_com_monstrum_go_proto_validators.WithMask(context.Background(), this, mask.GetPaths())
+	if err != nil {
+		return err
+	}
+	return this.ValidateAllContext(ctx)
+}
+func (this *UUIDMsg) ValidationRules() *github_com_monstrum_go_proto_validators.Rules {
+	return github_com_monstrum_go_proto_validators.MessageRules(this)
+}
+func init() {
+	github_com_monstrum_go_proto_validators.RegisterValidator("validator.examples.UUIDMsg", func() interface{} { return &UUIDMsg{} })
 }
@@ -1,5 +1,7 @@
 load("@bazel_gazelle//:deps.bzl", "go_repository")
 
+# Unmaintained: the dependencies of the runtime are missing, building with Bazel is not supported, see README.md.
+
 def go_repositories():
     go_repository(
         name = "com_github_davecgh_go_spew",
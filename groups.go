@@ -0,0 +1,63 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+// DefaultGroup is the validation group of the rules which are not tagged with any group. It is the group validated
+// by Validate and ValidateAll, and by ValidateGroups and ValidateAllGroups when no group is given.
+const DefaultGroup = "default"
+
+var defaultGroups = []string{DefaultGroup}
+
+// GroupsValidator is a general interface that allows a message to be validated for a set of validation groups.
+type GroupsValidator interface {
+	ValidateGroups(groups ...string) error
+}
+
+// GroupsValidators is a general interface that allows all message fields to be validated for a set of validation
+// groups.
+type GroupsValidators interface {
+	ValidateAllGroups(groups ...string) error
+}
+
+// InGroups reports whether the rules tagged with ruleGroups are checked when validating groups.
+func InGroups(groups []string, ruleGroups ...string) bool {
+	if len(groups) == 0 {
+		groups = defaultGroups
+	}
+	if len(ruleGroups) == 0 {
+		ruleGroups = defaultGroups
+	}
+	for _, group := range groups {
+		for _, ruleGroup := range ruleGroups {
+			if group == ruleGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CallGroupsValidatorIfExists validates candidate for groups. Messages generated without validation groups are
+// only validated as part of the default group.
+func CallGroupsValidatorIfExists(candidate interface{}, groups ...string) error {
+	if validator, ok := candidate.(GroupsValidator); ok {
+		return validator.ValidateGroups(groups...)
+	}
+	if InGroups(groups) {
+		return CallValidatorIfExists(candidate)
+	}
+	return nil
+}
+
+// CallGroupsValidatorsIfExists validates all fields of candidate for groups. Messages generated without validation
+// groups are only validated as part of the default group.
+func CallGroupsValidatorsIfExists(candidate interface{}, groups ...string) error {
+	if validator, ok := candidate.(GroupsValidators); ok {
+		return validator.ValidateAllGroups(groups...)
+	}
+	if InGroups(groups) {
+		return CallValidatorsIfExists(candidate)
+	}
+	return nil
+}
@@ -4,8 +4,12 @@
 package validator
 
 import (
-	"google.golang.org/protobuf/types/known/anypb"
+	"context"
+	"errors"
+	"fmt"
 	"strings"
+
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 type ValidationError struct {
@@ -13,15 +17,63 @@ type ValidationError struct {
 	Violation string
 	ErrorMsg  string
 	Index     int
-	Errors    *ValidationErrors
+	// Element is set when the violation is about the element Index of a repeated field rather than the field.
+	Element bool
+	Errors  *ValidationErrors
+	// Cause is the error the violation was reported with, if any.
+	Cause error
+}
+
+// Error describes the violation along with the path of its field, such as "Addresses[1].Zip", or the violations of a
+// nested message.
+func (e *ValidationError) Error() string {
+	if e.Errors.IsError() {
+		return e.Errors.summary(e.path())
+	}
+	return e.describe(e.path())
+}
+
+// Unwrap returns the violations of a nested message, or the cause of the violation.
+func (e *ValidationError) Unwrap() error {
+	if e.Errors.IsError() {
+		return e.Errors
+	}
+	return e.Cause
+}
+
+// path returns the path of the field of the violation, elements of repeated fields being indexed.
+func (e *ValidationError) path() string {
+	if e.Element || e.Violation == "array" || e.Violation == "unique" {
+		return fmt.Sprintf("%s[%d]", e.Field, e.Index)
+	}
+	return e.Field
+}
+
+func (e *ValidationError) describe(path string) string {
+	if path == "" {
+		return e.ErrorMsg
+	}
+	return "invalid field " + path + ": " + e.ErrorMsg
 }
 
 type ValidationErrors struct {
 	Errors []*ValidationError
+	// Warnings are the violations of rules with a warning severity, they don't fail the validation.
+	Warnings []*ValidationError
+
+	limits *limits
 }
 
 func (f *ValidationErrors) IsError() bool {
-	return len(f.Errors) > 0
+	return f != nil && len(f.Errors) > 0
+}
+
+// Err returns f if it holds errors, nil otherwise.
+func (f *ValidationErrors) Err() error {
+	if f.IsError() {
+		return f
+	}
+	return nil
 }
 
 func (f *ValidationErrors) Details() []*anypb.Any {
@@ -35,7 +87,16 @@ func (f *ValidationErrors) Details() []*anypb.Any {
 	return details
 }
 
+// AddValidationsError adds a violation of the element i of a repeated field.
 func (f *ValidationErrors) AddValidationsError(fieldName, violation string, i int, err interface{}) {
+	f.add(fieldName, violation, i, true, err)
+}
+
+func (f *ValidationErrors) AddValidationError(fieldName, violation string, err interface{}) {
+	f.add(fieldName, violation, 0, false, err)
+}
+
+func (f *ValidationErrors) add(fieldName, violation string, i int, element bool, err interface{}) {
 	message := "one or more items failed validation"
 	if violation == "message" {
 		message = "invalid"
@@ -45,37 +106,150 @@ func (f *ValidationErrors) AddValidationsError(fieldName, violation string, i in
 	case nil:
 		return
 	case *ValidationError:
-		v.ErrorMsg = message
-		f.Errors = append(f.Errors, v)
-	case *ValidationErrors:
+		// the violation of a nested message, it is wrapped so that the caller's error is left untouched
+		if !f.limits.addError() {
+			return
+		}
 		f.Errors = append(f.Errors, &ValidationError{
 			Field:     fieldName,
 			Violation: violation,
 			Index:     i,
-			Errors:    v,
+			Element:   element,
+			Errors:    &ValidationErrors{Errors: []*ValidationError{v}},
 			ErrorMsg:  message,
 		})
+	case *ValidationErrors:
+		nested := &ValidationError{
+			Field:     fieldName,
+			Violation: violation,
+			Index:     i,
+			Element:   element,
+			Errors:    v,
+			ErrorMsg:  message,
+		}
+		if !v.IsError() {
+			// a nested message only reporting warnings is valid
+			if len(v.Warnings) > 0 {
+				f.Warnings = append(f.Warnings, nested)
+			}
+			return
+		}
+		f.Errors = append(f.Errors, nested)
 	case string:
+		if !f.limits.addError() {
+			return
+		}
 		f.Errors = append(f.Errors, &ValidationError{
 			Field:     fieldName,
 			Violation: violation,
+			Index:     i,
+			Element:   element,
 			ErrorMsg:  v,
 		})
 	case error:
+		if !f.limits.addError() {
+			return
+		}
 		f.Errors = append(f.Errors, &ValidationError{
 			Field:     fieldName,
 			Violation: violation,
+			Index:     i,
+			Element:   element,
 			ErrorMsg:  v.Error(),
+			Cause:     v,
 		})
 	}
 }
 
-func (f *ValidationErrors) AddValidationError(fieldName, violation string, err interface{}) {
-	f.AddValidationsError(fieldName, violation, 0, err)
+// AddValidationWarning adds the violation of a rule with a warning severity.
+func (f *ValidationErrors) AddValidationWarning(fieldName, violation string, err interface{}) {
+	warnings := &ValidationErrors{}
+	warnings.AddValidationError(fieldName, violation, err)
+	f.Warnings = append(f.Warnings, warnings.Errors...)
+	f.Warnings = append(f.Warnings, warnings.Warnings...)
+}
+
+// AddValidationsWarning adds the violation of a rule with a warning severity by the element i of a repeated field.
+func (f *ValidationErrors) AddValidationsWarning(fieldName, violation string, i int, err interface{}) {
+	warnings := &ValidationErrors{}
+	warnings.AddValidationsError(fieldName, violation, i, err)
+	f.Warnings = append(f.Warnings, warnings.Errors...)
+	f.Warnings = append(f.Warnings, warnings.Warnings...)
+}
+
+// First returns the first violation of f along with the path of its field, as returned by Validate, nil if f holds
+// no error.
+func (f *ValidationErrors) First() error {
+	var first error
+	f.leaves("", func(path string, err *ValidationError) {
+		if first != nil {
+			return
+		}
+		first = err.Cause
+		if first == nil {
+			first = errors.New(err.ErrorMsg)
+		}
+		if path != "" {
+			first = FieldError(path, first)
+		}
+	})
+	return first
 }
 
+// errorSummaryLen is the number of violations described by the Error method of ValidationErrors.
+const errorSummaryLen = 5
+
+// Error describes the first violations found in the message and its nested messages, along with the paths of their
+// fields.
 func (f *ValidationErrors) Error() string {
-	return "bad request"
+	return f.summary("")
+}
+
+func (f *ValidationErrors) summary(prefix string) string {
+	var described []string
+	total := f.leaves(prefix, func(path string, err *ValidationError) {
+		if len(described) < errorSummaryLen {
+			described = append(described, err.describe(path))
+		}
+	})
+	if total == 0 {
+		return "bad request"
+	}
+	summary := strings.Join(described, "; ")
+	if total > len(described) {
+		summary += fmt.Sprintf(" (and %d more)", total-len(described))
+	}
+	return summary
+}
+
+// leaves calls visit with the violations of f and of its nested messages which are not nested messages themselves,
+// returning their number.
+func (f *ValidationErrors) leaves(prefix string, visit func(path string, err *ValidationError)) int {
+	total := 0
+	for _, err := range f.Errors {
+		path := err.path()
+		if prefix != "" && path != "" {
+			path = prefix + "." + path
+		} else if path == "" {
+			path = prefix
+		}
+		if err.Errors.IsError() {
+			total += err.Errors.leaves(path, visit)
+			continue
+		}
+		visit(path, err)
+		total++
+	}
+	return total
+}
+
+// Unwrap returns the violations, so that errors.As and errors.Is reach them and their causes.
+func (f *ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(f.Errors))
+	for _, err := range f.Errors {
+		errs = append(errs, err)
+	}
+	return errs
 }
 
 // Validator is a general interface that allows a message to be validated.
@@ -102,6 +276,109 @@ func CallValidatorsIfExists(candidate interface{}) error {
 	return nil
 }
 
+// WarningsValidators is a general interface that allows all message fields to be validated, reporting the violations
+// of the rules with a warning severity along with the errors.
+type WarningsValidators interface {
+	ValidateAllWithWarnings() *ValidationErrors
+}
+
+// CallValidatorsWithWarningsIfExists validates all fields of candidate. The returned error only holds warnings when
+// candidate is valid.
+func CallValidatorsWithWarningsIfExists(candidate interface{}) error {
+	if validator, ok := candidate.(WarningsValidators); ok {
+		if validations := validator.ValidateAllWithWarnings(); validations.IsError() || len(validations.Warnings) > 0 {
+			return validations
+		}
+		return nil
+	}
+	return CallValidatorsIfExists(candidate)
+}
+
+// ContextValidators is a general interface that allows all message fields to be validated with the Options carried
+// by a context.
+type ContextValidators interface {
+	ValidateAllContext(ctx context.Context) error
+}
+
+// ContextWarningsValidators is a general interface that allows all message fields to be validated with the Options
+// carried by a context, reporting the violations of the rules with a warning severity along with the errors. The
+// returned error is the error of the context if it is done before the validation completes.
+type ContextWarningsValidators interface {
+	ValidateAllContextWithWarnings(ctx context.Context) (*ValidationErrors, error)
+}
+
+// ValidateFirst validates candidate for groups, stopping on the first violation which is returned along with the
+// path of its field. It is used by the generated Validate and ValidateGroups.
+func ValidateFirst(candidate ContextWarningsValidators, groups ...string) error {
+	validations, err := candidate.ValidateAllContextWithWarnings(WithOptions(context.Background(), Groups(groups...), MaxErrors(1)))
+	if err != nil {
+		return err
+	}
+	return validations.First()
+}
+
+// ValidateContext validates all fields of msg with opts, notifying the Observer of the validation. Messages generated
+// without ValidateAllContext are validated with ValidateAllGroups or ValidateAll. It returns the error of ctx if it is
+// done before the validation completes. A validation hitting one of the limits of opts fails with a TooManyErrors
+// violation.
+func ValidateContext(ctx context.Context, msg interface{}, opts ...Option) error {
+	err := ValidateContextWithWarnings(ctx, msg, opts...)
+	if validations, ok := err.(*ValidationErrors); ok {
+		return validations.Err()
+	}
+	return err
+}
+
+// ValidateContextWithWarnings validates all fields of msg with opts like ValidateContext, returning the violations of
+// the rules with a warning severity along with the errors, and notifying the Observer of both. The returned error
+// only holds warnings when msg is valid.
+func ValidateContextWithWarnings(ctx context.Context, msg interface{}, opts ...Option) error {
+	ctx, options := withOptions(ctx, opts)
+	observer := options.Observer
+	if observer == nil {
+		observer = ObserverFromContext(ctx)
+	}
+	return observe(observer, msg, func() error {
+		return options.limits.truncate(callContextValidators(ctx, msg))
+	})
+}
+
+// CallContextValidatorsIfExists validates all fields of candidate with the Options of ctx. It is used by the
+// generated code to validate nested messages. The returned error only holds warnings when candidate is valid.
+func CallContextValidatorsIfExists(ctx context.Context, candidate interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	limits := OptionsFromContext(ctx).limits
+	if !limits.enter() {
+		return nil
+	}
+	defer limits.leave()
+	return callContextValidators(ctx, candidate)
+}
+
+// callContextValidators validates all fields of candidate with the Options of ctx, without counting it as a nested
+// message.
+func callContextValidators(ctx context.Context, candidate interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if validator, ok := candidate.(ContextWarningsValidators); ok {
+		validations, err := validator.ValidateAllContextWithWarnings(ctx)
+		if err != nil {
+			return err
+		}
+		if validations.IsError() || len(validations.Warnings) > 0 {
+			return validations
+		}
+		return nil
+	}
+	if validator, ok := candidate.(ContextValidators); ok {
+		return validator.ValidateAllContext(ctx)
+	}
+	return CallGroupsValidatorsIfExists(candidate, OptionsFromContext(ctx).Groups...)
+}
+
 type fieldError struct {
 	fieldStack []string
 	nestedErr  error
@@ -0,0 +1,60 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+func TestValidationErrors_AddValidationError(t *testing.T) {
+	nested := &validator.ValidationError{Field: "Zip", Violation: "regex", ErrorMsg: "value 'abc' must match"}
+	validations := &validator.ValidationErrors{}
+	validations.AddValidationsError("Addresses", "array", 1, nested)
+
+	assert.Equal(t, "value 'abc' must match", nested.ErrorMsg, "the violation added is left untouched")
+	assert.Equal(t, "invalid field Addresses[1].Zip: value 'abc' must match", validations.Error())
+	var violation *validator.ValidationError
+	assert.True(t, errors.As(validations, &violation))
+	assert.Equal(t, "Addresses", violation.Field)
+	assert.Same(t, nested, validations.Errors[0].Errors.Errors[0])
+}
+
+// legacyMessage is validated like the code generated before ValidateAllContext.
+type legacyMessage struct {
+	err error
+}
+
+func (m *legacyMessage) ValidateAll() error {
+	return m.err
+}
+
+// groupsMessage is a hand-written validator reading the options of the validation.
+type groupsMessage struct {
+	groups []string
+}
+
+func (m *groupsMessage) ValidateAllContext(ctx context.Context) error {
+	m.groups = validator.OptionsFromContext(ctx).Groups
+	return nil
+}
+
+func TestValidateContext_FallbackAndOptions(t *testing.T) {
+	legacy := &legacyMessage{err: errors.New("invalid")}
+	assert.Equal(t, legacy.err, validator.ValidateContext(context.Background(), legacy))
+	assert.NoError(t, validator.ValidateContext(context.Background(), legacy, validator.Groups("update")), "older messages only validate the default group")
+
+	message := &groupsMessage{}
+	assert.NoError(t, validator.ValidateContext(context.Background(), message, validator.Groups("update")))
+	assert.Equal(t, []string{"update"}, message.groups)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, validator.ValidateContext(ctx, &legacyMessage{}))
+}
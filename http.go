@@ -0,0 +1,290 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProblemContentType is the media type of the problem details written by WriteProblem.
+const ProblemContentType = "application/problem+json"
+
+// DefaultMaxBodyBytes is the size of the request bodies decoded by HTTPHandler and HTTPMiddleware unless set with
+// MaxBodyBytes.
+const DefaultMaxBodyBytes = 4 << 20
+
+// statusClientClosedRequest is the status of the requests cancelled by the client, a convention of nginx.
+const statusClientClosedRequest = 499
+
+// Problem is an RFC 9457 problem details document describing why the body of a request is invalid.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Errors are the violations of the validation rules, none if the body could not be decoded.
+	Errors []ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError is a violation listed in a Problem.
+type ProblemError struct {
+	// Field is the path of the violated field with the JSON names of the fields, such as "items[1].unitPrice".
+	Field string `json:"field,omitempty"`
+	// Pointer is the JSON Pointer of the violated field in the body, such as "#/items/1/unitPrice".
+	Pointer string `json:"pointer,omitempty"`
+	// Violation is the kind of the violation, such as "regex" or "required".
+	Violation string `json:"violation"`
+	// Detail describes the violation.
+	Detail string `json:"detail"`
+}
+
+// HTTPOption sets one of the settings of HTTPHandler and HTTPMiddleware.
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	maxBodyBytes int64
+	validation   []Option
+}
+
+// MaxBodyBytes sets the size of the request bodies decoded, DefaultMaxBodyBytes when 0 and unlimited when negative.
+func MaxBodyBytes(n int64) HTTPOption {
+	return func(o *httpOptions) {
+		o.maxBodyBytes = n
+	}
+}
+
+// ValidateWith sets the Options of the validation of the decoded messages.
+func ValidateWith(opts ...Option) HTTPOption {
+	return func(o *httpOptions) {
+		o.validation = append(o.validation, opts...)
+	}
+}
+
+type httpMessageKey struct{}
+
+// HTTPMiddleware returns a middleware decoding the protojson body of requests into a message returned by newMsg and
+// validating it with ValidateContext and the Options set with ValidateWith, the Observer of the request context being
+// notified. The next handler gets the valid message with HTTPMessage, invalid requests get a problem response written
+// by WriteProblem.
+func HTTPMiddleware(newMsg func() interface{}, opts ...HTTPOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return HTTPHandler(newMsg, func(w http.ResponseWriter, r *http.Request, msg interface{}) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), httpMessageKey{}, msg)))
+		}, opts...)
+	}
+}
+
+// HTTPHandler returns a handler calling handle with the valid messages decoded from the requests, like HTTPMiddleware.
+// Requests whose method is not POST, PUT or PATCH are rejected with a 405 problem response, those whose body is not
+// application/json with a 415 and bodies larger than the MaxBodyBytes of opts with a 413.
+func HTTPHandler(newMsg func() interface{}, handle func(w http.ResponseWriter, r *http.Request, msg interface{}), opts ...HTTPOption) http.Handler {
+	options := &httpOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg := newMsg()
+		m, ok := msg.(protoadapt.MessageV1)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unable to unmarshal %T", msg), http.StatusInternalServerError)
+			return
+		}
+		if err := checkRequest(r); err != nil {
+			if err.status == http.StatusMethodNotAllowed {
+				w.Header().Set("Allow", strings.Join(bodyMethods, ", "))
+			}
+			WriteProblem(w, msg, err)
+			return
+		}
+		body := io.Reader(r.Body)
+		if limit := options.maxBodyBytes; limit >= 0 {
+			if limit == 0 {
+				limit = DefaultMaxBodyBytes
+			}
+			body = http.MaxBytesReader(w, r.Body, limit)
+		}
+		if err := decodeJSON(body, m); err != nil {
+			WriteProblem(w, msg, err)
+			return
+		}
+		if err := ValidateContext(r.Context(), msg, options.validation...); err != nil {
+			WriteProblem(w, msg, err)
+			return
+		}
+		handle(w, r, msg)
+	})
+}
+
+// HTTPMessage returns the message decoded and validated by HTTPMiddleware for the request r, nil if there is none.
+func HTTPMessage(r *http.Request) interface{} {
+	return r.Context().Value(httpMessageKey{})
+}
+
+// bodyMethods are the methods of the requests whose bodies are decoded.
+var bodyMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch}
+
+// requestError is the error of a request whose body is not decoded, along with the status of the response.
+type requestError struct {
+	status int
+	detail string
+}
+
+func (e *requestError) Error() string {
+	return e.detail
+}
+
+// checkRequest checks that r is a request with a JSON body.
+func checkRequest(r *http.Request) *requestError {
+	if !containsString(bodyMethods, r.Method) {
+		return &requestError{http.StatusMethodNotAllowed, fmt.Sprintf("the method %s is not allowed", r.Method)}
+	}
+	contentType := r.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+		return &requestError{http.StatusUnsupportedMediaType, fmt.Sprintf("the request body must be application/json, not '%s'", contentType)}
+	}
+	return nil
+}
+
+// decodingError is an error of the decoding of a request body.
+type decodingError struct {
+	err error
+}
+
+func (e *decodingError) Error() string {
+	return e.err.Error()
+}
+
+func decodeJSON(body io.Reader, m protoadapt.MessageV1) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return &decodingError{err}
+	}
+	if err := protojson.Unmarshal(data, protoadapt.MessageV2Of(m)); err != nil {
+		return &decodingError{err}
+	}
+	return nil
+}
+
+// WriteProblem writes an RFC 9457 problem response for err, the error of the decoding or of the validation of the
+// request body msg. Violations of the rules are listed with the JSON paths of their fields in msg. The status is 400,
+// 413 if the body is too large, 499 if the client cancelled the request and 503 if the validation ran out of time.
+// Problems have the type about:blank, so their titles are the descriptions of their statuses.
+func WriteProblem(w http.ResponseWriter, msg interface{}, err error) {
+	problem := &Problem{Type: "about:blank", Status: http.StatusBadRequest, Detail: err.Error()}
+	switch e := err.(type) {
+	case *ValidationErrors:
+		problem.Detail = "the request body failed validation"
+		violationPaths(reflect.ValueOf(msg), e, nil, func(path []pathElement, v *ValidationError) {
+			field := formatPath(path, true)
+			problem.Errors = append(problem.Errors, ProblemError{
+				Field:     field,
+				Pointer:   jsonPointer(field),
+				Violation: v.Violation,
+				Detail:    v.ErrorMsg,
+			})
+		})
+	case *requestError:
+		problem.Status = e.status
+	case *decodingError:
+		var tooLarge *http.MaxBytesError
+		if errors.As(e.err, &tooLarge) {
+			problem.Status = http.StatusRequestEntityTooLarge
+			problem.Detail = fmt.Sprintf("the request body must not be larger than %d bytes", tooLarge.Limit)
+		}
+	default:
+		switch {
+		case errors.Is(err, context.Canceled):
+			problem.Status = statusClientClosedRequest
+		case errors.Is(err, context.DeadlineExceeded):
+			problem.Status = http.StatusServiceUnavailable
+		}
+	}
+	problem.Title = http.StatusText(problem.Status)
+	if problem.Status == statusClientClosedRequest {
+		problem.Title = "Client Closed Request"
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// pathElement is an element of the path of a violated field: a field, along with the index of an element for
+// repeated fields and -1 otherwise. Violations which are not bound to a field, such as those of validator.message
+// groups, only have a name.
+type pathElement struct {
+	field protoreflect.FieldDescriptor
+	name  string
+	index int
+}
+
+// violationPaths calls visit with the violations of validations and of its nested messages which are not nested
+// messages themselves, along with the path of their fields in the message v.
+func violationPaths(v reflect.Value, validations *ValidationErrors, prefix []pathElement, visit func(path []pathElement, err *ValidationError)) {
+	md := reflectDescriptor(v)
+	for _, err := range validations.Errors {
+		path := prefix
+		name, value, ok := maskField(v, err.Field)
+		if err.Field != "" {
+			element := pathElement{name: err.Field, index: -1}
+			if ok && md != nil {
+				element.field = md.Fields().ByName(protoreflect.Name(name))
+			}
+			if err.Element || err.Violation == "array" || err.Violation == "unique" {
+				element.index = err.Index
+				if value.Kind() == reflect.Slice && err.Index < value.Len() {
+					value = value.Index(err.Index)
+				}
+			}
+			path = append(path[:len(path):len(path)], element)
+		}
+		if err.Errors.IsError() {
+			violationPaths(value, err.Errors, path, visit)
+			continue
+		}
+		visit(path, err)
+	}
+}
+
+// formatPath writes a path such as "items[1].unitPrice", with the JSON names of the fields or their proto names.
+func formatPath(path []pathElement, jsonNames bool) string {
+	var b strings.Builder
+	for i, element := range path {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		switch {
+		case element.field == nil:
+			b.WriteString(element.name)
+		case jsonNames:
+			b.WriteString(element.field.JSONName())
+		default:
+			b.WriteString(string(element.field.Name()))
+		}
+		if element.index >= 0 {
+			fmt.Fprintf(&b, "[%d]", element.index)
+		}
+	}
+	return b.String()
+}
+
+// jsonPointer converts a path such as "items[1].unitPrice" to a JSON Pointer fragment, "#/items/1/unitPrice".
+func jsonPointer(path string) string {
+	if path == "" {
+		return ""
+	}
+	path = strings.NewReplacer("[", "/", "]", "", ".", "/").Replace(path)
+	return "#/" + path
+}
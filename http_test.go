@@ -0,0 +1,105 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// jsonRequest returns a POST request with a JSON body.
+func jsonRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/names", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return req
+}
+
+func problem(t *testing.T, rec *httptest.ResponseRecorder) *validator.Problem {
+	assert.Equal(t, validator.ProblemContentType, rec.Header().Get("Content-Type"))
+	p := &validator.Problem{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), p))
+	return p
+}
+
+func TestHTTPHandler_Requests(t *testing.T) {
+	var handled *wrapperspb.StringValue
+	handler := validator.HTTPHandler(func() interface{} { return &wrapperspb.StringValue{} }, func(w http.ResponseWriter, r *http.Request, msg interface{}) {
+		handled = msg.(*wrapperspb.StringValue)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest(`"alice"`))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "alice", handled.GetValue())
+
+	handled = nil
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest(`"alice`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	p := problem(t, rec)
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, "Bad Request", p.Title, "the title of about:blank problems is the description of the status")
+	assert.Empty(t, p.Errors)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/names", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "POST, PUT, PATCH", rec.Header().Get("Allow"))
+	assert.Equal(t, "Method Not Allowed", problem(t, rec).Title)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/names", strings.NewReader(`value: "alice"`))
+	req.Header.Set("Content-Type", "text/plain")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "the request body must be application/json, not 'text/plain'", problem(t, rec).Detail)
+	assert.Nil(t, handled, "requests which are not decoded don't reach the handler")
+}
+
+func TestHTTPHandler_LimitsAndCancellation(t *testing.T) {
+	handled := false
+	handler := validator.HTTPHandler(func() interface{} { return &wrapperspb.StringValue{} }, func(w http.ResponseWriter, r *http.Request, msg interface{}) {
+		handled = true
+	}, validator.MaxBodyBytes(16))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest(`"abcdefghijklmnop"`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	p := problem(t, rec)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, p.Status)
+	assert.Equal(t, "Request Entity Too Large", p.Title)
+	assert.Equal(t, "the request body must not be larger than 16 bytes", p.Detail)
+	assert.False(t, handled)
+
+	observer := &validator.MemoryObserver{}
+	handler = validator.HTTPHandler(func() interface{} { return &wrapperspb.StringValue{} }, func(w http.ResponseWriter, r *http.Request, msg interface{}) {
+		handled = true
+	}, validator.MaxBodyBytes(-1), validator.ValidateWith(validator.Observe(observer)))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest(`"abcdefghijklmnop"`))
+	assert.Equal(t, http.StatusOK, rec.Code, "bodies are not limited when MaxBodyBytes is negative")
+	assert.True(t, handled)
+	assert.Equal(t, 1, observer.Validations("google.protobuf.StringValue"), "the messages are validated with the options of ValidateWith")
+
+	rec = httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	validator.WriteProblem(rec, &wrapperspb.StringValue{}, ctx.Err())
+	assert.Equal(t, 499, rec.Code, "requests cancelled by the client are not reported as unavailable")
+	assert.Equal(t, "Client Closed Request", problem(t, rec).Title)
+
+	rec = httptest.NewRecorder()
+	validator.WriteProblem(rec, &wrapperspb.StringValue{}, context.DeadlineExceeded)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import "context"
+
+// TooManyErrors is the violation added to the ValidationErrors of the validated message when a limit of the Options is
+// hit, the message being only partially validated.
+const TooManyErrors = "too_many_errors"
+
+// limits keeps track of the MaxErrors, MaxDepth and MaxRepeatedItems options during a validation.
+type limits struct {
+	options *Options
+	errors  int
+	depth   int
+	reached bool
+	// root collects the violations of the validated message, the other ValidationErrors being nested in it.
+	root *ValidationErrors
+}
+
+func newLimits(options *Options) *limits {
+	if options.MaxErrors <= 0 && options.MaxDepth <= 0 && options.MaxRepeatedItems <= 0 {
+		return nil
+	}
+	return &limits{options: options}
+}
+
+// addError reports whether one more violation can be collected.
+func (l *limits) addError() bool {
+	if l == nil {
+		return true
+	}
+	if l.options.MaxErrors > 0 && l.errors >= l.options.MaxErrors {
+		l.reached = true
+		return false
+	}
+	l.errors++
+	return true
+}
+
+func (l *limits) full() bool {
+	return l != nil && l.options.MaxErrors > 0 && l.errors >= l.options.MaxErrors
+}
+
+// enter reports whether one more level of nested messages can be traversed, leave must be called when it does.
+func (l *limits) enter() bool {
+	if l == nil {
+		return true
+	}
+	if l.full() || l.options.MaxDepth > 0 && l.depth >= l.options.MaxDepth {
+		l.reached = true
+		return false
+	}
+	l.depth++
+	return true
+}
+
+func (l *limits) leave() {
+	if l != nil {
+		l.depth--
+	}
+}
+
+// NewValidationErrors returns the ValidationErrors collecting the violations of a message validated with the Options
+// of ctx, within their limits. Complete must be called once the message is validated.
+func NewValidationErrors(ctx context.Context) *ValidationErrors {
+	validations := &ValidationErrors{limits: OptionsFromContext(ctx).limits}
+	if l := validations.limits; l != nil && l.root == nil {
+		l.root = validations
+	}
+	return validations
+}
+
+// Complete returns f once its message is validated. The ValidationErrors of the validated message, as opposed to
+// those of its nested messages, get the TooManyErrors violation if a limit was hit.
+func (f *ValidationErrors) Complete() *ValidationErrors {
+	if l := f.limits; l != nil && l.root == f {
+		l.root = nil
+		l.truncate(f)
+	}
+	return f
+}
+
+// Inspect reports whether the element i of a repeated field is validated, which stops once MaxRepeatedItems elements
+// were inspected or MaxErrors violations were collected.
+func (f *ValidationErrors) Inspect(i int) bool {
+	l := f.limits
+	if l == nil {
+		return true
+	}
+	if l.full() || l.options.MaxRepeatedItems > 0 && i >= l.options.MaxRepeatedItems {
+		l.reached = true
+		return false
+	}
+	return true
+}
+
+// Truncated reports whether the validation stopped on a limit, in which case the last error is a TooManyErrors
+// violation.
+func (f *ValidationErrors) Truncated() bool {
+	return len(f.Errors) > 0 && f.Errors[len(f.Errors)-1].Violation == TooManyErrors
+}
+
+// truncate adds the TooManyErrors violation to the result of a validation which hit a limit, unless it was added by
+// Complete.
+func (l *limits) truncate(err error) error {
+	if l == nil || !l.reached {
+		return err
+	}
+	validations, ok := err.(*ValidationErrors)
+	if !ok {
+		if err != nil {
+			return err
+		}
+		validations = &ValidationErrors{}
+	}
+	if validations.Truncated() {
+		return validations
+	}
+	validations.Errors = append(validations.Errors, &ValidationError{
+		Violation: TooManyErrors,
+		ErrorMsg:  "too many errors, the message was only partially validated",
+	})
+	return validations
+}
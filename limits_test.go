@@ -0,0 +1,69 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// node is validated like the generated code: its names must not be empty and its child is a nested message.
+type node struct {
+	names []string
+	child *node
+}
+
+func (n *node) ValidateAllContextWithWarnings(ctx context.Context) (*validator.ValidationErrors, error) {
+	validations := validator.NewValidationErrors(ctx)
+	for i, name := range n.names {
+		if !validations.Inspect(i) {
+			break
+		}
+		if name == "" {
+			validations.AddValidationsError("Names", "string_not_empty", i, "value must not be an empty string")
+		}
+	}
+	if n.child != nil {
+		if err := validator.CallContextValidatorsIfExists(ctx, n.child); err != nil {
+			if _, ok := err.(*validator.ValidationErrors); !ok {
+				return nil, err
+			}
+			validations.AddValidationError("Child", "message", err)
+		}
+	}
+	return validations.Complete(), nil
+}
+
+func TestLimits_Marker(t *testing.T) {
+	tree := &node{names: []string{"", ""}, child: &node{names: []string{"", "", ""}, child: &node{}}}
+
+	validations, err := tree.ValidateAllContextWithWarnings(validator.WithOptions(context.Background(), validator.MaxErrors(3)))
+	assert.NoError(t, err)
+	assert.True(t, validations.Truncated(), "the validated message gets the marker")
+	assert.Len(t, validations.Errors, 4)
+	assert.False(t, validations.Errors[2].Errors.Truncated(), "nested messages don't")
+
+	validations, err = tree.ValidateAllContextWithWarnings(validator.WithOptions(context.Background(), validator.MaxDepth(1)))
+	assert.NoError(t, err)
+	assert.True(t, validations.Truncated())
+	assert.Len(t, validations.Errors[2].Errors.Errors, 3, "the child is traversed, not the grandchild")
+
+	validations, err = tree.ValidateAllContextWithWarnings(validator.WithOptions(context.Background(), validator.MaxRepeatedItems(1)))
+	assert.NoError(t, err)
+	assert.True(t, validations.Truncated())
+	assert.Len(t, validations.Errors, 3, "one name of each message and the marker")
+
+	validations, err = tree.ValidateAllContextWithWarnings(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, validations.Truncated(), "there are no limits by default")
+
+	err = validator.ValidateContext(context.Background(), tree, validator.MaxDepth(1))
+	assert.True(t, err.(*validator.ValidationErrors).Truncated())
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 4)
+	assert.Equal(t, validator.TooManyErrors, err.(*validator.ValidationErrors).Errors[3].Violation, "the marker is only added once")
+}
@@ -0,0 +1,365 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LocatedViolation is a violation along with its position in the input its message was decoded from.
+type LocatedViolation struct {
+	*ValidationError
+	// Path is the path of the violated field, such as "items[1].unitPrice" for JSON inputs and "items[1].unit_price" for
+	// text inputs.
+	Path string
+	// Line and Column are the 1-based position of the field in the input, columns counting characters. Fields which
+	// are not set in the input are located at the closest enclosing field or message which is.
+	Line   int
+	Column int
+}
+
+// String describes the violation with its position, such as "12:5: invalid field items[1].unitPrice: ...", for
+// messages in the style of compilers and editors.
+func (v *LocatedViolation) String() string {
+	return fmt.Sprintf("%d:%d: %s", v.Line, v.Column, v.describe(v.Path))
+}
+
+// LocateJSON returns the violations of err, the error of the validation of msg decoded from the protojson input data,
+// along with their positions in data. err is returned by ValidateAll or by Validate, which only reports the first
+// violation. JSON names and proto names of the fields are both recognized.
+func LocateJSON(data []byte, msg interface{}, err error) ([]*LocatedViolation, error) {
+	root, scanErr := scanJSON(data)
+	if scanErr != nil {
+		return nil, fmt.Errorf("unable to scan the JSON input: %v", scanErr)
+	}
+	return locate(data, root, msg, err, true)
+}
+
+// LocateText returns the violations of err, the error of the validation of msg decoded from the prototext input data,
+// along with their positions in data.
+func LocateText(data []byte, msg interface{}, err error) ([]*LocatedViolation, error) {
+	s := &textScanner{data: data}
+	root, scanErr := s.message(0)
+	if scanErr != nil {
+		return nil, fmt.Errorf("unable to scan the text input: %v", scanErr)
+	}
+	return locate(data, root, msg, err, false)
+}
+
+func locate(data []byte, root *inputNode, msg interface{}, err error, jsonNames bool) ([]*LocatedViolation, error) {
+	if err == nil {
+		return nil, nil
+	}
+	var validations *ValidationErrors
+	var fErr *fieldError
+	switch {
+	case errors.As(err, &validations):
+	case errors.As(err, &fErr):
+		validations = fErr.validations()
+	default:
+		return nil, fmt.Errorf("unable to locate the violations of %T", err)
+	}
+	var located []*LocatedViolation
+	violationPaths(reflect.ValueOf(msg), validations, nil, func(path []pathElement, v *ValidationError) {
+		line, column := lineColumn(data, root.find(path))
+		located = append(located, &LocatedViolation{
+			ValidationError: v,
+			Path:            formatPath(path, jsonNames),
+			Line:            line,
+			Column:          column,
+		})
+	})
+	return located, nil
+}
+
+// validations converts the error returned by Validate to ValidationErrors, with a violation nested along the fields of
+// its stack, such as "Items[1]" and "Name".
+func (f *fieldError) validations() *ValidationErrors {
+	var names []string
+	for _, field := range f.fieldStack {
+		names = append(names, strings.Split(field, ".")...)
+	}
+	violation := &ValidationError{Violation: "invalid", ErrorMsg: f.nestedErr.Error(), Cause: f.nestedErr}
+	for i := len(names) - 1; i >= 0; i-- {
+		if i < len(names)-1 {
+			violation = &ValidationError{Violation: "message", ErrorMsg: "invalid", Errors: &ValidationErrors{Errors: []*ValidationError{violation}}}
+		}
+		violation.Field = names[i]
+		if open := strings.IndexByte(names[i], '['); open > 0 && strings.HasSuffix(names[i], "]") {
+			if index, err := strconv.Atoi(names[i][open+1 : len(names[i])-1]); err == nil {
+				violation.Field, violation.Index, violation.Element = names[i][:open], index, true
+			}
+		}
+	}
+	return &ValidationErrors{Errors: []*ValidationError{violation}}
+}
+
+// inputNode is a value of an input, a message holding fields or a scalar.
+type inputNode struct {
+	offset int
+	fields map[string]*inputField
+	// elements are the elements of a JSON array.
+	elements []*inputNode
+}
+
+// inputField is a field of a message in an input, holding the elements of repeated fields in order.
+type inputField struct {
+	offset   int
+	elements []*inputNode
+}
+
+// find returns the offset of the field of path, or of the closest enclosing field or message in the input.
+func (n *inputNode) find(path []pathElement) int {
+	offset := n.offset
+	for _, element := range path {
+		var field *inputField
+		if element.field != nil {
+			if field = n.fields[element.field.JSONName()]; field == nil {
+				field = n.fields[string(element.field.Name())]
+			}
+		}
+		if field == nil {
+			return offset
+		}
+		offset = field.offset
+		switch {
+		case element.index >= 0 && element.index < len(field.elements):
+			n = field.elements[element.index]
+			offset = n.offset
+		case element.index < 0 && len(field.elements) == 1:
+			n = field.elements[0]
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineColumn converts an offset of data to a line and a column.
+func lineColumn(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return line, utf8.RuneCount(data[lineStart:offset]) + 1
+}
+
+func scanJSON(data []byte) (*inputNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return scanJSONValue(dec, data)
+}
+
+func scanJSONValue(dec *json.Decoder, data []byte) (*inputNode, error) {
+	node := &inputNode{offset: skipJSONSeparators(data, int(dec.InputOffset()))}
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		node.fields = map[string]*inputField{}
+		for dec.More() {
+			offset := skipJSONSeparators(data, int(dec.InputOffset()))
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := scanJSONValue(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			field := &inputField{offset: offset, elements: []*inputNode{value}}
+			if value.elements != nil {
+				field.elements = value.elements
+			}
+			node.fields[fmt.Sprint(key)] = field
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case json.Delim('['):
+		node.elements = []*inputNode{}
+		for dec.More() {
+			element, err := scanJSONValue(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			node.elements = append(node.elements, element)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// skipJSONSeparators returns the offset of the next token of data from offset, past the separators ending the
+// previous one.
+func skipJSONSeparators(data []byte, offset int) int {
+	for offset < len(data) && bytes.IndexByte([]byte(" \t\r\n,:"), data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// textScanner scans the messages of a prototext input.
+type textScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *textScanner) skip() {
+	for s.pos < len(s.data) {
+		switch c := s.data[s.pos]; {
+		case c == '#':
+			for s.pos < len(s.data) && s.data[s.pos] != '\n' {
+				s.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// message scans the fields of a message up to its closing delimiter end, or to the end of the input for 0.
+func (s *textScanner) message(end byte) (*inputNode, error) {
+	node := &inputNode{offset: s.pos, fields: map[string]*inputField{}}
+	if end != 0 {
+		node.offset--
+	}
+	for {
+		s.skip()
+		if s.pos == len(s.data) {
+			if end != 0 {
+				return nil, fmt.Errorf("unexpected end of input, expected '%c'", end)
+			}
+			return node, nil
+		}
+		if s.data[s.pos] == end {
+			s.pos++
+			return node, nil
+		}
+		offset := s.pos
+		name, err := s.name()
+		if err != nil {
+			return nil, err
+		}
+		field := node.fields[name]
+		if field == nil {
+			field = &inputField{offset: offset}
+			node.fields[name] = field
+		}
+		s.skip()
+		if s.pos < len(s.data) && s.data[s.pos] == ':' {
+			s.pos++
+			s.skip()
+		}
+		if s.pos < len(s.data) && s.data[s.pos] == '[' {
+			s.pos++
+			for {
+				s.skip()
+				if s.pos < len(s.data) && s.data[s.pos] == ']' {
+					s.pos++
+					break
+				}
+				value, err := s.value()
+				if err != nil {
+					return nil, err
+				}
+				field.elements = append(field.elements, value)
+				s.skip()
+				if s.pos < len(s.data) && s.data[s.pos] == ',' {
+					s.pos++
+				}
+			}
+		} else {
+			value, err := s.value()
+			if err != nil {
+				return nil, err
+			}
+			field.elements = append(field.elements, value)
+		}
+		s.skip()
+		if s.pos < len(s.data) && (s.data[s.pos] == ',' || s.data[s.pos] == ';') {
+			s.pos++
+		}
+	}
+}
+
+// name scans the name of a field, an identifier or an extension or type URL between brackets.
+func (s *textScanner) name() (string, error) {
+	start := s.pos
+	if s.data[s.pos] == '[' {
+		end := bytes.IndexByte(s.data[s.pos:], ']')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated name at offset %d", start)
+		}
+		s.pos += end + 1
+		return string(s.data[start:s.pos]), nil
+	}
+	for s.pos < len(s.data) && isTextIdentifier(s.data[s.pos]) {
+		s.pos++
+	}
+	if s.pos == start {
+		return "", fmt.Errorf("unexpected '%c' at offset %d", s.data[s.pos], start)
+	}
+	return string(s.data[start:s.pos]), nil
+}
+
+// value scans a message between braces or angle brackets, or a scalar.
+func (s *textScanner) value() (*inputNode, error) {
+	if s.pos == len(s.data) {
+		return nil, fmt.Errorf("unexpected end of input, expected a value")
+	}
+	start := s.pos
+	switch c := s.data[s.pos]; c {
+	case '{', '<':
+		s.pos++
+		end := byte('}')
+		if c == '<' {
+			end = '>'
+		}
+		return s.message(end)
+	case '"', '\'':
+		// adjacent strings are concatenated
+		for s.pos < len(s.data) && (s.data[s.pos] == '"' || s.data[s.pos] == '\'') {
+			quote := s.data[s.pos]
+			s.pos++
+			for s.pos < len(s.data) && s.data[s.pos] != quote {
+				if s.data[s.pos] == '\\' {
+					s.pos++
+				}
+				s.pos++
+			}
+			if s.pos >= len(s.data) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			s.pos++
+			s.skip()
+		}
+		return &inputNode{offset: start}, nil
+	}
+	for s.pos < len(s.data) && (isTextIdentifier(s.data[s.pos]) || s.data[s.pos] == '-' || s.data[s.pos] == '+') {
+		s.pos++
+	}
+	if s.pos == start {
+		return nil, fmt.Errorf("unexpected '%c' at offset %d", s.data[s.pos], start)
+	}
+	return &inputNode{offset: start}, nil
+}
+
+func isTextIdentifier(c byte) bool {
+	return c == '_' || c == '.' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,45 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+func TestLocate_RepeatedScalar(t *testing.T) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"name", "Email"}}
+	validations := &validator.ValidationErrors{}
+	validations.AddValidationsError("Paths", "regex", 1, "value 'Email' must be lower case")
+
+	located, err := validator.LocateJSON([]byte("{\n  \"paths\": [\"name\", \"Email\"]\n}"), mask, validations)
+	assert.NoError(t, err)
+	if assert.Len(t, located, 1) {
+		assert.Equal(t, "paths[1]", located[0].Path)
+		assert.Equal(t, []int{2, 21}, []int{located[0].Line, located[0].Column}, "the element is located, not the array")
+	}
+
+	located, err = validator.LocateText([]byte("paths: \"name\"\npaths: \"Email\""), mask, validations)
+	assert.NoError(t, err)
+	if assert.Len(t, located, 1) {
+		assert.Equal(t, "paths[1]", located[0].Path)
+		assert.Equal(t, []int{2, 8}, []int{located[0].Line, located[0].Column})
+	}
+}
+
+func TestLocate_FieldError(t *testing.T) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"name", "Email"}}
+	err := validator.FieldError("Paths[1]", errors.New("value 'Email' must be lower case"))
+
+	located, locateErr := validator.LocateText([]byte("paths: \"name\"\npaths: \"Email\""), mask, err)
+	assert.NoError(t, locateErr)
+	if assert.Len(t, located, 1, "the first violation returned by Validate is located as well") {
+		assert.Equal(t, "2:8: invalid field paths[1]: value 'Email' must be lower case", located[0].String())
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldMask is satisfied by both the golang and the gogo flavours of google.protobuf.FieldMask.
+type fieldMask interface {
+	GetPaths() []string
+}
+
+// ValidateMask validates the fields of msg listed in paths, nested fields being written as "address.zip" and the
+// paths of a repeated message field applying to each of its elements. An empty list of paths, or the "*" path,
+// validates the whole message. The rules of the fields which are not listed are not checked. Rules of the message
+// itself are checked when they involve a listed field: required fields, field groups and required oneofs, CEL
+// expressions of validator.message only being checked for the whole message. Paths which are not fields of msg are
+// rejected. The returned violations hold the warnings of the listed fields along with their errors.
+func ValidateMask(msg interface{}, paths []string) error {
+	validations, err := ValidateMaskWithWarnings(msg, paths)
+	if err != nil {
+		return err
+	}
+	return validations.Err()
+}
+
+// ValidateMaskWithWarnings validates the fields of msg listed in paths like ValidateMask, returning the warnings along
+// with the errors even when msg is valid. The error is only set when msg or paths cannot be validated.
+func ValidateMaskWithWarnings(msg interface{}, paths []string) (*ValidationErrors, error) {
+	var m protoreflect.Message
+	switch t := msg.(type) {
+	case proto.Message:
+		m = t.ProtoReflect()
+	case protoadapt.MessageV1:
+		m = protoadapt.MessageV2Of(t).ProtoReflect()
+	default:
+		return nil, fmt.Errorf("unable to validate the fields of %T", msg)
+	}
+	if err := checkFieldMaskPaths(paths, m.Descriptor(), true); err != nil {
+		return nil, err
+	}
+	_, options := withOptions(context.Background(), nil)
+	return validateReflect(m, options, newValidationMask(paths))
+}
+
+// WithMask returns a context restricting the validation of msg to the fields listed in paths, as ValidateMask does.
+// It is used by the generated ValidateMask. Paths which are not fields of msg are rejected.
+func WithMask(ctx context.Context, msg interface{}, paths []string) (context.Context, error) {
+	var md protoreflect.MessageDescriptor
+	switch t := msg.(type) {
+	case proto.Message:
+		md = t.ProtoReflect().Descriptor()
+	case protoadapt.MessageV1:
+		md = protoadapt.MessageV2Of(t).ProtoReflect().Descriptor()
+	default:
+		return nil, fmt.Errorf("unable to validate the fields of %T", msg)
+	}
+	if err := checkFieldMaskPaths(paths, md, true); err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, maskKey{}, newValidationMask(paths)), nil
+}
+
+type maskKey struct{}
+
+// MaskFromContext returns the mask restricting the validation of the message validated with ctx, nil if every field
+// is validated.
+func MaskFromContext(ctx context.Context) ValidationMask {
+	mask, _ := ctx.Value(maskKey{}).(ValidationMask)
+	return mask
+}
+
+// ValidationMask restricts a validation to the fields it lists by name, the mask of a message field restricting the
+// validation of its value. A nil mask validates everything.
+type ValidationMask map[string]ValidationMask
+
+func newValidationMask(paths []string) ValidationMask {
+	mask := ValidationMask{}
+	for _, path := range paths {
+		if path == "*" {
+			return nil
+		}
+		mask.add(strings.Split(path, "."))
+	}
+	if len(mask) == 0 {
+		return nil
+	}
+	return mask
+}
+
+func (m ValidationMask) add(names []string) {
+	sub, seen := m[names[0]]
+	switch {
+	case seen && sub == nil:
+		// the whole field is already listed
+	case len(names) == 1:
+		m[names[0]] = nil
+	default:
+		if sub == nil {
+			sub = ValidationMask{}
+			m[names[0]] = sub
+		}
+		sub.add(names[1:])
+	}
+}
+
+// Lists reports whether one of the fields names is validated, the rules involving them being checked.
+func (m ValidationMask) Lists(names ...string) bool {
+	for _, name := range names {
+		if _, ok := m[name]; ok {
+			return true
+		}
+	}
+	return m == nil
+}
+
+// Context returns the context validating the value of the field name, restricted to the fields listed under it.
+func (m ValidationMask) Context(ctx context.Context, name string) context.Context {
+	if m == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, maskKey{}, m[name])
+}
+
+// field returns the mask of the value of the field name.
+func (m ValidationMask) field(name protoreflect.Name) ValidationMask {
+	return m[string(name)]
+}
+
+// maskField looks up the Go field goName of the message v, returning its name in the message descriptor and its
+// value.
+func maskField(v reflect.Value, goName string) (string, reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if _, ok := field.Tag.Lookup("protobuf_oneof"); ok {
+			// the value of a oneof is held by a wrapper struct with a single field.
+			if name, value, ok := maskField(v.Field(i), goName); ok {
+				return name, value, true
+			}
+			continue
+		}
+		if field.Name != goName {
+			continue
+		}
+		for _, opt := range strings.Split(field.Tag.Get("protobuf"), ",") {
+			if name := strings.TrimPrefix(opt, "name="); name != opt {
+				return name, v.Field(i), true
+			}
+		}
+	}
+	return "", reflect.Value{}, false
+}
+
+// CheckFieldMask returns an error if a path of mask is not a field path of the message type of target.
+func CheckFieldMask(mask fieldMask, target interface{}) error {
+	var md protoreflect.MessageDescriptor
+	switch t := target.(type) {
+	case proto.Message:
+		md = t.ProtoReflect().Descriptor()
+	case protoadapt.MessageV1:
+		md = protoadapt.MessageV2Of(t).ProtoReflect().Descriptor()
+	default:
+		return fmt.Errorf("unable to check field mask paths against %T", target)
+	}
+	return checkFieldMaskPaths(mask.GetPaths(), md, false)
+}
+
+// checkFieldMaskPaths returns an error if a path is not a field path of md, paths going through repeated message
+// fields being accepted with elements.
+func checkFieldMaskPaths(paths []string, md protoreflect.MessageDescriptor, elements bool) error {
+	for _, path := range paths {
+		if path == "*" {
+			continue
+		}
+		if !isFieldPath(md, path, elements) {
+			return fmt.Errorf("path '%s' is not a field of %s", path, md.FullName())
+		}
+	}
+	return nil
+}
+
+func isFieldPath(md protoreflect.MessageDescriptor, path string, elements bool) bool {
+	name, rest, nested := strings.Cut(path, ".")
+	fd := md.Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		return false
+	}
+	if !nested {
+		return true
+	}
+	if fd.Message() == nil || (fd.IsList() && !elements) || fd.IsMap() {
+		return false
+	}
+	return isFieldPath(fd.Message(), rest, elements)
+}
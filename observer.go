@@ -0,0 +1,219 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Observer is notified of validations, for instance to count the violations of each message type for dashboards.
+// Message names are the full names of the validated message types, paths the proto names of the violated fields,
+// such as "address.zip" or "items[2].name".
+type Observer interface {
+	// OnValidate is called when a message is validated.
+	OnValidate(msgName string)
+	// OnViolation is called for each violation found when validating a message.
+	OnViolation(msgName, path, kind string)
+	// OnDuration is called with the time spent validating a message.
+	OnDuration(msgName string, d time.Duration)
+}
+
+// WarningObserver is implemented by the Observers also notified of the violations of the rules with a warning
+// severity, which don't fail the validation.
+type WarningObserver interface {
+	// OnWarning is called for each warning found when validating a message.
+	OnWarning(msgName, path, kind string)
+}
+
+type observerHolder struct {
+	observer Observer
+}
+
+var globalObserver atomic.Value
+
+// SetObserver registers the Observer used when none is set on the context, nil removes it.
+func SetObserver(o Observer) {
+	globalObserver.Store(observerHolder{o})
+}
+
+type observerKey struct{}
+
+// WithObserver returns a context carrying an Observer, which takes precedence over the one set with SetObserver.
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+// ObserverFromContext returns the Observer of ctx, or the one set with SetObserver. It returns nil if there is none.
+func ObserverFromContext(ctx context.Context) Observer {
+	if ctx != nil {
+		if o, ok := ctx.Value(observerKey{}).(Observer); ok {
+			return o
+		}
+	}
+	holder, _ := globalObserver.Load().(observerHolder)
+	return holder.observer
+}
+
+// CallObservedValidatorsIfExists validates all fields of candidate like CallValidatorsIfExists, notifying the
+// Observer of ctx. It is meant for the helpers validating requests, such as server interceptors.
+func CallObservedValidatorsIfExists(ctx context.Context, candidate interface{}) error {
+	err := observe(ObserverFromContext(ctx), candidate, func() error {
+		return CallValidatorsWithWarningsIfExists(candidate)
+	})
+	if validations, ok := err.(*ValidationErrors); ok {
+		return validations.Err()
+	}
+	return err
+}
+
+// observe notifies o of the validation of candidate by validate, whose error may only hold warnings.
+func observe(o Observer, candidate interface{}, validate func() error) error {
+	if o == nil {
+		return validate()
+	}
+	start := time.Now()
+	msgName := messageName(candidate)
+	o.OnValidate(msgName)
+	err := validate()
+	if validations, ok := err.(*ValidationErrors); ok {
+		warnings, _ := o.(WarningObserver)
+		observeViolations(o, warnings, msgName, reflect.ValueOf(candidate), validations, "", false)
+	} else if err != nil {
+		o.OnViolation(msgName, "", "invalid")
+	}
+	o.OnDuration(msgName, time.Since(start))
+	return err
+}
+
+func messageName(candidate interface{}) string {
+	if md := reflectDescriptor(reflect.ValueOf(candidate)); md != nil {
+		return string(md.FullName())
+	}
+	return fmt.Sprintf("%T", candidate)
+}
+
+// observeViolations notifies o of the errors of validations and warnings of its warnings, all of them being warnings
+// when warning is set.
+func observeViolations(o Observer, warnings WarningObserver, msgName string, v reflect.Value, validations *ValidationErrors, prefix string, warning bool) {
+	visit := func(err *ValidationError, warning bool) {
+		path := prefix
+		name, value, ok := maskField(v, err.Field)
+		if !ok {
+			name = err.Field
+		}
+		if name != "" && path != "" {
+			path += "."
+		}
+		path += name
+		if err.Errors == nil {
+			if err.Element {
+				path = fmt.Sprintf("%s[%d]", path, err.Index)
+			}
+			if !warning {
+				o.OnViolation(msgName, path, err.Violation)
+			} else if warnings != nil {
+				warnings.OnWarning(msgName, path, err.Violation)
+			}
+			return
+		}
+		if value.Kind() == reflect.Slice && err.Index < value.Len() {
+			path = fmt.Sprintf("%s[%d]", path, err.Index)
+			value = value.Index(err.Index)
+		}
+		observeViolations(o, warnings, msgName, value, err.Errors, path, warning)
+	}
+	for _, err := range validations.Errors {
+		visit(err, warning)
+	}
+	for _, err := range validations.Warnings {
+		visit(err, true)
+	}
+}
+
+// ObservedViolation is a violation recorded by a MemoryObserver.
+type ObservedViolation struct {
+	Message string
+	Path    string
+	Kind    string
+}
+
+// MemoryObserver is an Observer keeping what it is notified of in memory, mostly for tests. The zero value is ready
+// to use.
+type MemoryObserver struct {
+	mu          sync.Mutex
+	validations map[string]int
+	violations  []ObservedViolation
+	warnings    []ObservedViolation
+	durations   map[string][]time.Duration
+}
+
+func (o *MemoryObserver) OnValidate(msgName string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.validations == nil {
+		o.validations = map[string]int{}
+	}
+	o.validations[msgName]++
+}
+
+func (o *MemoryObserver) OnViolation(msgName, path, kind string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.violations = append(o.violations, ObservedViolation{Message: msgName, Path: path, Kind: kind})
+}
+
+func (o *MemoryObserver) OnWarning(msgName, path, kind string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.warnings = append(o.warnings, ObservedViolation{Message: msgName, Path: path, Kind: kind})
+}
+
+func (o *MemoryObserver) OnDuration(msgName string, d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.durations == nil {
+		o.durations = map[string][]time.Duration{}
+	}
+	o.durations[msgName] = append(o.durations[msgName], d)
+}
+
+// Validations returns the number of validations of a message type.
+func (o *MemoryObserver) Validations(msgName string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.validations[msgName]
+}
+
+// Violations returns the violations recorded so far, in order.
+func (o *MemoryObserver) Violations() []ObservedViolation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]ObservedViolation(nil), o.violations...)
+}
+
+// Warnings returns the warnings recorded so far, in order.
+func (o *MemoryObserver) Warnings() []ObservedViolation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]ObservedViolation(nil), o.warnings...)
+}
+
+// Durations returns the durations of the validations of a message type.
+func (o *MemoryObserver) Durations(msgName string) []time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]time.Duration(nil), o.durations[msgName]...)
+}
+
+// Reset forgets everything recorded so far.
+func (o *MemoryObserver) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.validations, o.violations, o.warnings, o.durations = nil, nil, nil, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+func TestObserver_Global(t *testing.T) {
+	global := &validator.MemoryObserver{}
+	validator.SetObserver(global)
+	defer validator.SetObserver(nil)
+	invalid := fixedValidators{&validator.ValidationErrors{}}
+	invalid.validations.AddValidationError("Name", "string_not_empty", "value must not be an empty string")
+	invalid.validations.AddValidationWarning("Nickname", "length_lt", "value is long")
+
+	assert.Error(t, validator.CallObservedValidatorsIfExists(context.Background(), invalid))
+	assert.Equal(t, 1, global.Validations("validator_test.fixedValidators"))
+	assert.Equal(t, []validator.ObservedViolation{{Message: "validator_test.fixedValidators", Path: "Name", Kind: "string_not_empty"}}, global.Violations())
+	assert.Equal(t, []validator.ObservedViolation{{Message: "validator_test.fixedValidators", Path: "Nickname", Kind: "length_lt"}}, global.Warnings())
+	assert.Len(t, global.Durations("validator_test.fixedValidators"), 1)
+
+	local := &validator.MemoryObserver{}
+	assert.Error(t, validator.CallObservedValidatorsIfExists(validator.WithObserver(context.Background(), local), invalid))
+	assert.Equal(t, 1, local.Validations("validator_test.fixedValidators"), "the observer of the context takes precedence")
+	assert.Equal(t, 1, global.Validations("validator_test.fixedValidators"))
+
+	global.Reset()
+	assert.Empty(t, global.Violations())
+	assert.Empty(t, global.Warnings())
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Options are the settings of a validation started with ValidateContext. They are carried by the context passed down
+// to nested messages and to hand-written validators, which read them with OptionsFromContext.
+type Options struct {
+	// Observer is notified of the validation, instead of the Observer of the context.
+	Observer Observer
+	// Groups are the validation groups to validate, the default group when empty.
+	Groups []string
+	// MaxErrors is the number of violations collected before the validation stops, unlimited when 0.
+	MaxErrors int
+	// MaxDepth is the number of nested messages traversed from the validated message, unlimited when 0.
+	MaxDepth int
+	// MaxRepeatedItems is the number of elements inspected in each repeated field, unlimited when 0.
+	MaxRepeatedItems int
+	// AnyResolver looks up the message types packed into google.protobuf.Any fields annotated with any_validate,
+	// protoregistry.GlobalTypes when nil.
+	AnyResolver protoregistry.MessageTypeResolver
+
+	limits *limits
+}
+
+// Option sets one of the Options of ValidateContext.
+type Option func(*Options)
+
+// Observe sets the Observer notified of the validation.
+func Observe(observer Observer) Option {
+	return func(o *Options) {
+		o.Observer = observer
+	}
+}
+
+// Groups sets the validation groups to validate.
+func Groups(groups ...string) Option {
+	return func(o *Options) {
+		o.Groups = groups
+	}
+}
+
+// MaxErrors sets the number of violations collected before the validation stops.
+func MaxErrors(n int) Option {
+	return func(o *Options) {
+		o.MaxErrors = n
+	}
+}
+
+// MaxDepth sets the number of nested messages traversed from the validated message.
+func MaxDepth(n int) Option {
+	return func(o *Options) {
+		o.MaxDepth = n
+	}
+}
+
+// MaxRepeatedItems sets the number of elements inspected in each repeated field.
+func MaxRepeatedItems(n int) Option {
+	return func(o *Options) {
+		o.MaxRepeatedItems = n
+	}
+}
+
+// ResolveAny sets the resolver looking up the message types packed into google.protobuf.Any fields.
+func ResolveAny(resolver protoregistry.MessageTypeResolver) Option {
+	return func(o *Options) {
+		o.AnyResolver = resolver
+	}
+}
+
+type optionsKey struct{}
+
+var defaultOptions = &Options{}
+
+// OptionsFromContext returns the Options of the validation running with ctx, the defaults if there is none.
+func OptionsFromContext(ctx context.Context) *Options {
+	if o, ok := ctx.Value(optionsKey{}).(*Options); ok {
+		return o
+	}
+	return defaultOptions
+}
+
+// WithOptions returns a context carrying the options of ctx updated with opts, to validate messages with their
+// ValidateAllContext method. The limits of the options are shared by the validations using the returned context.
+func WithOptions(ctx context.Context, opts ...Option) context.Context {
+	ctx, _ = withOptions(ctx, opts)
+	return ctx
+}
+
+// withOptions returns a context carrying the options of ctx updated with opts, for a new validation.
+func withOptions(ctx context.Context, opts []Option) (context.Context, *Options) {
+	options := *OptionsFromContext(ctx)
+	for _, opt := range opts {
+		opt(&options)
+	}
+	options.limits = newLimits(&options)
+	return context.WithValue(ctx, optionsKey{}, &options), &options
+}
@@ -0,0 +1,151 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// celRules lists the CEL rules of a message, field rules first in field order, then message rules.
+func (p *plugin) celRules(message *generator.Descriptor) []*validator.CELRule {
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	var rules []*validator.CELRule
+	for _, field := range message.Field {
+		for _, set := range p.fieldRuleSets(field, true) {
+			if len(set.fv.GetItems().GetCel()) > 0 {
+				log.Printf("WARNING: field %v.%v has validator.items cel rules, cel rules are only supported on the field itself\n", ccTypeName, field.GetName())
+			}
+			for _, c := range set.fv.GetCel() {
+				groups := c.GetGroups()
+				if len(groups) == 0 {
+					groups = set.groups
+				}
+				warning := isWarning(set.fv)
+				if c.Severity != nil {
+					warning = c.GetSeverity() == validator.Severity_SEVERITY_WARNING
+				}
+				rules = append(rules, &validator.CELRule{
+					Field:      p.GetOneOfFieldName(message, field),
+					ProtoName:  field.GetName(),
+					Id:         c.GetId(),
+					Message:    c.GetMessage(),
+					Expression: c.GetExpression(),
+					Groups:     groups,
+					Warning:    warning,
+				})
+			}
+		}
+	}
+	for _, c := range getMessageValidatorIfAny(message.DescriptorProto).GetCel() {
+		rules = append(rules, &validator.CELRule{
+			Id:         c.GetId(),
+			Message:    c.GetMessage(),
+			Expression: c.GetExpression(),
+			Groups:     c.GetGroups(),
+			Warning:    c.GetSeverity() == validator.Severity_SEVERITY_WARNING,
+		})
+	}
+	return rules
+}
+
+func (p *plugin) generateCELVars(file *generator.FileDescriptor, message *generator.Descriptor) {
+	rules := p.celRules(message)
+	if len(rules) == 0 {
+		return
+	}
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	md := p.messageDescriptor(file, message)
+	for _, rule := range rules {
+		if _, err := validator.CompileCELRule(md, rule); err != nil {
+			p.Fail(fmt.Sprintf("cel rule of %v is invalid: %v", ccTypeName, err))
+		}
+	}
+	p.P(`var `, p.celName(ccTypeName), ` = `, p.validatorPkg.Use(), `.NewCELValidator(`)
+	p.In()
+	for _, rule := range rules {
+		var fields []string
+		for _, kv := range [][2]string{{"Field", rule.Field}, {"ProtoName", rule.ProtoName}, {"Id", rule.Id}, {"Message", rule.Message}, {"Expression", rule.Expression}} {
+			if kv[1] != "" {
+				fields = append(fields, kv[0]+": "+strconv.Quote(kv[1]))
+			}
+		}
+		if len(rule.Groups) > 0 {
+			groups := make([]string, 0, len(rule.Groups))
+			for _, group := range rule.Groups {
+				groups = append(groups, strconv.Quote(group))
+			}
+			fields = append(fields, "Groups: []string{"+strings.Join(groups, ", ")+"}")
+		}
+		if rule.Warning {
+			fields = append(fields, "Warning: true")
+		}
+		p.P(`&`, p.validatorPkg.Use(), `.CELRule{`, strings.Join(fields, ", "), `},`)
+	}
+	p.Out()
+	p.P(`)`)
+}
+
+func (p *plugin) generateCELValidator(_ *generator.FileDescriptor, message *generator.Descriptor) {
+	if len(p.celRules(message)) == 0 {
+		return
+	}
+	p.usesGroups, p.usesMask = true, true
+	p.P(p.celName(generator.CamelCaseSlice(message.TypeName())), `.ValidateAllMask(this, validations, groups, mask)`)
+}
+
+func (p *plugin) celName(ccTypeName string) string {
+	return "_cel_" + ccTypeName
+}
+
+// messageDescriptor resolves the descriptor of a message out of the files of the generator request.
+func (p *plugin) messageDescriptor(file *generator.FileDescriptor, message *generator.Descriptor) protoreflect.MessageDescriptor {
+	if p.files == nil {
+		files, err := loadFiles(p.Request.ProtoFile)
+		if err != nil {
+			p.Fail(err.Error())
+		}
+		p.files = files
+	}
+	name := messageFullName(file, message)
+	d, err := p.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		p.Fail(fmt.Sprintf("unable to find the descriptor of %v: %v", name, err))
+	}
+	return d.(protoreflect.MessageDescriptor)
+}
+
+// loadFiles builds the descriptors of the files of a generator request.
+func loadFiles(protoFiles []*descriptor.FileDescriptorProto) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, f := range protoFiles {
+		data, err := proto.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := protov2.Unmarshal(data, fd); err != nil {
+			return nil, err
+		}
+		set.File = append(set.File, fd)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the descriptors: %v", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,412 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// docFile is the documentation of the validation rules of the messages of a proto file.
+type docFile struct {
+	Path     string
+	Messages []*docMessage
+}
+
+type docMessage struct {
+	FullName protoreflect.FullName
+	Comment  string
+	Fields   []*docField
+	// Rules are the rules of the message spanning several fields.
+	Rules []string
+}
+
+type docField struct {
+	Name     string
+	JSONName string
+	Type     string
+	Comment  string
+	Rules    []string
+	Errors   []string
+}
+
+// GenerateDoc generates the documentation of the validation rules of the messages of each file to generate,
+// "<dir>/<file>.validators.md" with tables of fields, their rules phrased like the errors of the generated code and
+// their proto comments. With opts.DocFormat "html", an HTML page "<dir>/<file>.validators.html" is generated instead.
+func GenerateDoc(req *plugin_go.CodeGeneratorRequest, opts Options) (*plugin_go.CodeGeneratorResponse, error) {
+	format := opts.DocFormat
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "html" {
+		return nil, fmt.Errorf("unknown documentation format '%s'", format)
+	}
+	files, err := requestFiles(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	for _, fd := range files {
+		doc := &docFile{Path: fd.Path()}
+		for _, md := range fileMessages(fd) {
+			doc.Messages = append(doc.Messages, messageDoc(md, opts.ExplicitItemRules))
+		}
+		name := strings.TrimSuffix(fd.Path(), ".proto") + ".validators.md"
+		content := markdownDoc(doc)
+		if format == "html" {
+			name = strings.TrimSuffix(fd.Path(), ".proto") + ".validators.html"
+			if content, err = htmlDoc(doc); err != nil {
+				return nil, err
+			}
+		}
+		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(name),
+			Content: proto.String(content),
+		})
+	}
+	return resp, nil
+}
+
+func messageDoc(md protoreflect.MessageDescriptor, explicitItemRules bool) *docMessage {
+	rules := validator.DescriptorRules(md)
+	doc := &docMessage{FullName: md.FullName(), Comment: comments(md)}
+	switch {
+	case rules.Message.GetDisabled():
+		doc.Rules = append(doc.Rules, "validation is disabled")
+		rules = &validator.Rules{FullName: rules.FullName}
+	case rules.Message.GetSkipNested():
+		doc.Rules = append(doc.Rules, "not validated as a field of other messages")
+	}
+	required := map[string]bool{}
+	for _, name := range rules.Message.GetRequired() {
+		required[name] = true
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		field := &docField{Name: string(fd.Name()), JSONName: fd.JSONName(), Type: fieldType(fd), Comment: comments(fd)}
+		if required[field.Name] {
+			field.Rules = append(field.Rules, "must be set")
+		}
+		if fv := rules.Fields[field.Name]; fv != nil {
+			for _, set := range append([]*validator.FieldValidator{fv}, fv.GetGroupRules()...) {
+				suffix := ""
+				if len(set.GetGroups()) > 0 {
+					suffix += " (groups: " + strings.Join(set.GetGroups(), ", ") + ")"
+				}
+				if isWarning(set) {
+					suffix += " (warning)"
+				}
+				for _, text := range fieldRuleTexts(fd, set, explicitItemRules) {
+					field.Rules = append(field.Rules, text+suffix)
+				}
+				if set.HumanError != nil && !containsString(field.Errors, set.GetHumanError()) {
+					field.Errors = append(field.Errors, set.GetHumanError())
+				}
+			}
+		}
+		doc.Fields = append(doc.Fields, field)
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if rules.Oneofs[string(od.Name())].GetRequired() {
+			var names []string
+			for j := 0; j < od.Fields().Len(); j++ {
+				names = append(names, string(od.Fields().Get(j).Name()))
+			}
+			doc.Rules = append(doc.Rules, fmt.Sprintf("one of the fields %s of %s must be set", strings.Join(names, ", "), od.Name()))
+		}
+	}
+	for _, group := range rules.Message.GetAtLeastOneOf() {
+		doc.Rules = append(doc.Rules, fmt.Sprintf("one of the fields %s must be set", strings.Join(group.GetFields(), ", ")))
+	}
+	for _, group := range rules.Message.GetAtMostOneOf() {
+		doc.Rules = append(doc.Rules, fmt.Sprintf("at most one of the fields %s can be set", strings.Join(group.GetFields(), ", ")))
+	}
+	for _, c := range rules.Message.GetCel() {
+		doc.Rules = append(doc.Rules, constraintText("the message must satisfy the expression", c))
+	}
+	return doc
+}
+
+// fieldType returns the type of a field as written in a proto file.
+func fieldType(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return "map<" + fieldType(fd.MapKey()) + ", " + fieldType(fd.MapValue()) + ">"
+	}
+	name := fd.Kind().String()
+	switch {
+	case fd.Message() != nil:
+		name = string(fd.Message().FullName())
+	case fd.Enum() != nil:
+		name = string(fd.Enum().FullName())
+	}
+	if fd.IsList() {
+		return "repeated " + name
+	}
+	return name
+}
+
+// fieldRuleTexts phrases the rules of a validator of a field like the errors of the generated code.
+func fieldRuleTexts(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator, explicitItemRules bool) []string {
+	if fd.IsList() {
+		collection, items := repeatedRules(fd, fv, explicitItemRules)
+		texts := collectionRuleTexts(fd, collection)
+		if items != nil {
+			for _, text := range valueRuleTexts(fd, items) {
+				texts = append(texts, "each element "+text)
+			}
+		}
+		return texts
+	}
+	return append(collectionRuleTexts(fd, fv), valueRuleTexts(fd, fv)...)
+}
+
+// collectionRuleTexts phrases the rules applying to a field as a whole.
+func collectionRuleTexts(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) []string {
+	var texts []string
+	if fv.GetRequired() {
+		texts = append(texts, "must be set")
+	}
+	if fv.GetMsgExists() && !fd.IsList() {
+		texts = append(texts, "must exist")
+	}
+	if fd.IsList() {
+		if fv.RepeatedCountMin != nil {
+			texts = append(texts, fmt.Sprint("must contain at least ", fv.GetRepeatedCountMin(), " elements"))
+		}
+		if fv.RepeatedCountMax != nil {
+			texts = append(texts, fmt.Sprint("must contain at most ", fv.GetRepeatedCountMax(), " elements"))
+		}
+		texts = append(texts, lengthRuleTexts(fv)...)
+		if fv.GetUnique() {
+			texts = append(texts, "must be unique")
+		}
+		if fv.UniqueBy != nil {
+			texts = append(texts, fmt.Sprintf("must have a unique '%s'", fv.GetUniqueBy()))
+		}
+	}
+	for _, rule := range []struct {
+		text      string
+		condition *validator.FieldCondition
+	}{
+		{"must be set when ", fv.GetRequiredIf()},
+		{"must be set unless ", fv.GetRequiredUnless()},
+		{"must not be set when ", fv.GetForbiddenIf()},
+	} {
+		if rule.condition == nil {
+			continue
+		}
+		description := rule.condition.GetField() + " is set"
+		if len(rule.condition.GetIn()) > 0 {
+			description = rule.condition.GetField() + " is " + strings.Join(rule.condition.GetIn(), " or ")
+		}
+		texts = append(texts, rule.text+description)
+	}
+	if fv.GetImmutable() {
+		texts = append(texts, "must not change")
+	}
+	switch fv.GetMonotonic() {
+	case validator.Monotonic_MONOTONIC_INCREASING:
+		texts = append(texts, "must be greater than or equal to the previous value")
+	case validator.Monotonic_MONOTONIC_STRICTLY_INCREASING:
+		texts = append(texts, "must be greater than the previous value")
+	case validator.Monotonic_MONOTONIC_DECREASING:
+		texts = append(texts, "must be less than or equal to the previous value")
+	case validator.Monotonic_MONOTONIC_STRICTLY_DECREASING:
+		texts = append(texts, "must be less than the previous value")
+	}
+	for _, transition := range fv.GetTransitions() {
+		texts = append(texts, fmt.Sprintf("can only change from '%s' to '%s'", transition.GetFrom(), strings.Join(transition.GetTo(), "', '")))
+	}
+	return texts
+}
+
+// valueRuleTexts phrases the rules applying to a single value.
+func valueRuleTexts(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) []string {
+	var texts []string
+	if fv.Regex != nil || fv.UuidVer != nil {
+		regex := fv.GetRegex()
+		if fv.UuidVer != nil {
+			if uuid, err := getUUIDRegex(fv.UuidVer); err == nil {
+				regex = uuid
+			}
+		}
+		texts = append(texts, "must be a string conforming to regex "+strconv.Quote(regex))
+	}
+	if fv.GetStringNotEmpty() {
+		texts = append(texts, "must not be an empty string")
+	}
+	if fv.IntGt != nil {
+		texts = append(texts, fmt.Sprintf("must be greater than '%d'", fv.GetIntGt()))
+	}
+	if fv.IntLt != nil {
+		texts = append(texts, fmt.Sprintf("must be less than '%d'", fv.GetIntLt()))
+	}
+	tolerance := ""
+	if fv.GetFloatEpsilon() != 0 {
+		tolerance = fmt.Sprintf(" with a tolerance of '%g'", fv.GetFloatEpsilon())
+	}
+	if fv.FloatGt != nil {
+		texts = append(texts, fmt.Sprintf("must be strictly greater than '%g'", fv.GetFloatGt())+tolerance)
+	}
+	if fv.FloatGte != nil {
+		texts = append(texts, fmt.Sprintf("must be greater than or equal to '%g'", fv.GetFloatGte()))
+	}
+	if fv.FloatLt != nil {
+		texts = append(texts, fmt.Sprintf("must be strictly lower than '%g'", fv.GetFloatLt())+tolerance)
+	}
+	if fv.FloatLte != nil {
+		texts = append(texts, fmt.Sprintf("must be lower than or equal to '%g'", fv.GetFloatLte()))
+	}
+	if !fd.IsList() {
+		texts = append(texts, lengthRuleTexts(fv)...)
+	}
+	if fv.GetIsInEnum() && fd.Enum() != nil {
+		texts = append(texts, fmt.Sprintf("must be a valid %s field", fd.Enum().FullName()))
+	}
+	if len(fv.GetAnyIn()) > 0 {
+		texts = append(texts, fmt.Sprintf("must have a type URL in '%s'", strings.Join(validator.AnyTypeURLs(fv.GetAnyIn()), ", ")))
+	}
+	if len(fv.GetAnyNotIn()) > 0 {
+		texts = append(texts, fmt.Sprintf("must not have a type URL in '%s'", strings.Join(validator.AnyTypeURLs(fv.GetAnyNotIn()), ", ")))
+	}
+	if fv.GetAnyValidate() {
+		texts = append(texts, "must hold a valid message of a registered type")
+	}
+	if fv.FieldMaskTarget != nil {
+		texts = append(texts, fmt.Sprintf("must only have paths of fields of %s", fv.GetFieldMaskTarget()))
+	}
+	for _, c := range fv.GetCel() {
+		texts = append(texts, constraintText("must satisfy the expression", c))
+	}
+	return texts
+}
+
+func lengthRuleTexts(fv *validator.FieldValidator) []string {
+	var texts []string
+	if fv.LengthGt != nil {
+		texts = append(texts, fmt.Sprintf("must have a length greater than '%d'", fv.GetLengthGt()))
+	}
+	if fv.LengthLt != nil {
+		texts = append(texts, fmt.Sprintf("must have a length smaller than '%d'", fv.GetLengthLt()))
+	}
+	if fv.LengthEq != nil {
+		texts = append(texts, fmt.Sprintf("must have a length equal to '%d'", fv.GetLengthEq()))
+	}
+	return texts
+}
+
+// constraintText phrases a CEL constraint, with its message if it has one.
+func constraintText(prefix string, c *validator.Constraint) string {
+	text := fmt.Sprintf("%s '%s'", prefix, c.GetExpression())
+	if c.GetMessage() != "" {
+		text += ": " + c.GetMessage()
+	}
+	if len(c.GetGroups()) > 0 {
+		text += " (groups: " + strings.Join(c.GetGroups(), ", ") + ")"
+	}
+	if c.GetSeverity() == validator.Severity_SEVERITY_WARNING {
+		text += " (warning)"
+	}
+	return text
+}
+
+func markdownDoc(doc *docFile) string {
+	var buf bytes.Buffer
+	buf.WriteString("# " + doc.Path + "\n")
+	for _, message := range doc.Messages {
+		buf.WriteString("\n## " + string(message.FullName) + "\n\n")
+		if message.Comment != "" {
+			buf.WriteString(message.Comment + "\n\n")
+		}
+		if len(message.Fields) > 0 {
+			buf.WriteString("| Field | Type | Rules | Error | Description |\n")
+			buf.WriteString("| --- | --- | --- | --- | --- |\n")
+			for _, field := range message.Fields {
+				name := "`" + field.Name + "`"
+				if field.JSONName != field.Name {
+					name += " (`" + field.JSONName + "`)"
+				}
+				buf.WriteString("| " + name + " | `" + field.Type + "` | " + markdownCell(field.Rules...) + " | " +
+					markdownCell(field.Errors...) + " | " + markdownCell(field.Comment) + " |\n")
+			}
+		}
+		if len(message.Rules) > 0 {
+			if len(message.Fields) > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("Message rules:\n\n")
+			for _, rule := range message.Rules {
+				buf.WriteString("- " + rule + "\n")
+			}
+		}
+	}
+	return buf.String()
+}
+
+// markdownCell joins lines into the content of a table cell, escaping the characters ending it.
+func markdownCell(lines ...string) string {
+	escaped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		for _, l := range strings.Split(line, "\n") {
+			escaped = append(escaped, strings.Replace(strings.TrimSpace(l), "|", `\|`, -1))
+		}
+	}
+	return strings.Join(escaped, "<br>")
+}
+
+var htmlTemplate = template.Must(template.New("doc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Path}}</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{.Path}}</h1>
+{{- range .Messages}}
+<h2 id="{{.FullName}}">{{.FullName}}</h2>
+{{- if .Comment}}
+<p>{{.Comment}}</p>
+{{- end}}
+{{- if .Fields}}
+<table>
+<tr><th>Field</th><th>Type</th><th>Rules</th><th>Error</th><th>Description</th></tr>
+{{- range .Fields}}
+<tr><td><code>{{.Name}}</code>{{if ne .JSONName .Name}} (<code>{{.JSONName}}</code>){{end}}</td><td><code>{{.Type}}</code></td><td>{{range $i, $r := .Rules}}{{if $i}}<br>{{end}}{{$r}}{{end}}</td><td>{{range $i, $e := .Errors}}{{if $i}}<br>{{end}}{{$e}}{{end}}</td><td>{{.Comment}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Rules}}
+<p>Message rules:</p>
+<ul>
+{{- range .Rules}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func htmlDoc(doc *docFile) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,480 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"path"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gogo/protobuf/proto"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a JSON Schema 2020-12, which OpenAPI 3.1 uses for its schema objects as well.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Comment              string                 `json:"$comment,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              json.Number            `json:"minimum,omitempty"`
+	ExclusiveMinimum     json.Number            `json:"exclusiveMinimum,omitempty"`
+	Maximum              json.Number            `json:"maximum,omitempty"`
+	ExclusiveMaximum     json.Number            `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int64                 `json:"minLength,omitempty"`
+	MaxLength            *int64                 `json:"maxLength,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	MinItems             *int64                 `json:"minItems,omitempty"`
+	MaxItems             *int64                 `json:"maxItems,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
+	Properties           *jsonProperties        `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AllOf                []*jsonSchema          `json:"allOf,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	OneOf                []*jsonSchema          `json:"oneOf,omitempty"`
+	Not                  *jsonSchema            `json:"not,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// jsonProperties are the properties of an object schema, written in the order of the fields.
+type jsonProperties struct {
+	names   []string
+	schemas map[string]*jsonSchema
+}
+
+func (p *jsonProperties) add(name string, schema *jsonSchema) {
+	if p.schemas == nil {
+		p.schemas = map[string]*jsonSchema{}
+	}
+	p.names = append(p.names, name)
+	p.schemas[name] = schema
+}
+
+func (p *jsonProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range p.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonSchemaGenerator builds the JSON Schemas of message types from their descriptors, following the protojson
+// mapping. The schemas of the referenced message types are collected in defs.
+type jsonSchemaGenerator struct {
+	explicitItemRules bool
+	// refPrefix is prepended to the full names of message types to reference their schemas.
+	refPrefix string
+	defs      map[string]*jsonSchema
+}
+
+// GenerateJSONSchema generates a JSON Schema 2020-12 document for each message of the files to generate, named after
+// the full name of the message: "<dir>/<full name>.schema.json".
+func GenerateJSONSchema(req *plugin_go.CodeGeneratorRequest, opts Options) (*plugin_go.CodeGeneratorResponse, error) {
+	files, err := requestFiles(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	for _, fd := range files {
+		for _, md := range fileMessages(fd) {
+			g := &jsonSchemaGenerator{explicitItemRules: opts.ExplicitItemRules, refPrefix: "#/$defs/", defs: map[string]*jsonSchema{}}
+			g.ref(md)
+			name := path.Join(path.Dir(fd.Path()), string(md.FullName())+".schema.json")
+			content, err := json.MarshalIndent(&jsonSchema{
+				Schema: jsonSchemaDialect,
+				ID:     path.Base(name),
+				Ref:    g.refPrefix + string(md.FullName()),
+				Defs:   g.defs,
+			}, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+				Name:    proto.String(name),
+				Content: proto.String(string(content) + "\n"),
+			})
+		}
+	}
+	return resp, nil
+}
+
+// ref returns a reference to the schema of a message type, adding it to the definitions if needed.
+func (g *jsonSchemaGenerator) ref(md protoreflect.MessageDescriptor) *jsonSchema {
+	if wkt := wellKnownSchema(md); wkt != nil {
+		return wkt
+	}
+	name := string(md.FullName())
+	if _, ok := g.defs[name]; !ok {
+		// set first, in case the message type references itself
+		g.defs[name] = &jsonSchema{}
+		g.defs[name] = g.messageSchema(md)
+	}
+	return &jsonSchema{Ref: g.refPrefix + name}
+}
+
+// messageSchema returns the schema of a message type, with the rules of its fields and its message and oneof rules.
+func (g *jsonSchemaGenerator) messageSchema(md protoreflect.MessageDescriptor) *jsonSchema {
+	rules := descriptorRules(md)
+	s := &jsonSchema{
+		Title:       string(md.Name()),
+		Description: comments(md),
+		Type:        "object",
+		Properties:  &jsonProperties{},
+	}
+	required := map[string]bool{}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		sets := defaultRuleSets(rules.Fields[string(fd.Name())])
+		property := g.fieldSchema(fd, sets)
+		property.Description = comments(fd)
+		s.Properties.add(fd.JSONName(), property)
+		if fd.Cardinality() == protoreflect.Required {
+			required[fd.JSONName()] = true
+		}
+		for _, fv := range sets {
+			if fv.GetRequired() || (fv.GetMsgExists() && fd.Message() != nil && !fd.IsList() && !fd.IsMap()) {
+				required[fd.JSONName()] = true
+			}
+			if len(fv.GetCel()) > 0 || fv.RequiredIf != nil || fv.RequiredUnless != nil || fv.ForbiddenIf != nil || fv.UniqueBy != nil {
+				log.Printf("WARNING: field %v.%v has cel, conditional or unique_by rules, they are not part of the schema\n", md.FullName(), fd.Name())
+			}
+		}
+	}
+	mv := rules.Message
+	for _, name := range mv.GetRequired() {
+		if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+			required[fd.JSONName()] = true
+		}
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		if name := md.Fields().Get(i).JSONName(); required[name] {
+			s.Required = append(s.Required, name)
+		}
+	}
+	var constraints []*jsonSchema
+	for _, group := range mv.GetAtLeastOneOf() {
+		constraints = append(constraints, &jsonSchema{AnyOf: requiredEach(md, group.GetFields())})
+	}
+	for _, group := range mv.GetAtMostOneOf() {
+		var pairs []*jsonSchema
+		names := jsonNames(md, group.GetFields())
+		for i := range names {
+			for j := i + 1; j < len(names); j++ {
+				pairs = append(pairs, &jsonSchema{Required: []string{names[i], names[j]}})
+			}
+		}
+		if len(pairs) > 0 {
+			constraints = append(constraints, &jsonSchema{Not: &jsonSchema{AnyOf: pairs}})
+		}
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if !rules.Oneofs[string(od.Name())].GetRequired() {
+			continue
+		}
+		var names []string
+		for j := 0; j < od.Fields().Len(); j++ {
+			names = append(names, string(od.Fields().Get(j).Name()))
+		}
+		constraints = append(constraints, &jsonSchema{OneOf: requiredEach(md, names)})
+	}
+	if len(mv.GetCel()) > 0 {
+		log.Printf("WARNING: message %v has cel rules, they are not part of the schema\n", md.FullName())
+	}
+	if len(constraints) == 1 {
+		s.AnyOf, s.OneOf, s.Not = constraints[0].AnyOf, constraints[0].OneOf, constraints[0].Not
+	} else {
+		s.AllOf = constraints
+	}
+	return s
+}
+
+// fieldSchema returns the schema of the value of a field in its message.
+func (g *jsonSchemaGenerator) fieldSchema(fd protoreflect.FieldDescriptor, sets []*validator.FieldValidator) *jsonSchema {
+	switch {
+	case fd.IsMap():
+		return &jsonSchema{Type: "object", AdditionalProperties: g.valueSchema(fd.MapValue(), nil)}
+	case fd.IsList():
+		var collections, items []*validator.FieldValidator
+		for _, fv := range sets {
+			collection, item := repeatedRules(fd, fv, g.explicitItemRules)
+			collections = append(collections, collection)
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+		s := &jsonSchema{Type: "array", Items: g.valueSchema(fd, items)}
+		for _, fv := range collections {
+			s.MinItems = maxBound(s.MinItems, fv.RepeatedCountMin, 0)
+			s.MaxItems = minBound(s.MaxItems, fv.RepeatedCountMax, 0)
+			s.MinItems = maxBound(s.MinItems, fv.LengthGt, 1)
+			s.MaxItems = minBound(s.MaxItems, fv.LengthLt, -1)
+			s.MinItems = maxBound(s.MinItems, fv.LengthEq, 0)
+			s.MaxItems = minBound(s.MaxItems, fv.LengthEq, 0)
+			s.UniqueItems = s.UniqueItems || fv.GetUnique()
+		}
+		return s
+	}
+	return g.valueSchema(fd, sets)
+}
+
+// valueSchema returns the schema of a single value of a field, checked by the rules of sets.
+func (g *jsonSchemaGenerator) valueSchema(fd protoreflect.FieldDescriptor, sets []*validator.FieldValidator) *jsonSchema {
+	var s *jsonSchema
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		s = g.ref(fd.Message())
+	case protoreflect.EnumKind:
+		s = &jsonSchema{Type: []string{"string", "integer"}}
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			s = &jsonSchema{Type: "null"}
+		}
+	default:
+		s = scalarSchema(fd.Kind())
+	}
+	for i, fv := range sets {
+		if i == 0 {
+			applyJSONRules(s, fd, fv)
+			continue
+		}
+		extra := &jsonSchema{}
+		applyJSONRules(extra, fd, fv)
+		s.AllOf = append(s.AllOf, extra)
+	}
+	return s
+}
+
+func scalarSchema(kind protoreflect.Kind) *jsonSchema {
+	switch kind {
+	case protoreflect.BoolKind:
+		return &jsonSchema{Type: "boolean"}
+	case protoreflect.StringKind:
+		return &jsonSchema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &jsonSchema{Type: "string", ContentEncoding: "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &jsonSchema{Type: "number"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// protojson writes 64-bit integers as strings
+		return &jsonSchema{Type: []string{"integer", "string"}, Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &jsonSchema{Type: []string{"integer", "string"}, Format: "uint64"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &jsonSchema{Type: "integer", Format: "uint32"}
+	}
+	return &jsonSchema{Type: "integer", Format: "int32"}
+}
+
+// wellKnownSchema returns the schema of the well-known types having a special protojson mapping, nil for other types.
+func wellKnownSchema(md protoreflect.MessageDescriptor) *jsonSchema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &jsonSchema{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]{1,9})?s$`}
+	case "google.protobuf.FieldMask":
+		return &jsonSchema{Type: "string"}
+	case "google.protobuf.Struct":
+		return &jsonSchema{Type: "object"}
+	case "google.protobuf.ListValue":
+		return &jsonSchema{Type: "array"}
+	case "google.protobuf.Value":
+		return &jsonSchema{}
+	case "google.protobuf.Empty":
+		return &jsonSchema{Type: "object", AdditionalProperties: false}
+	case "google.protobuf.Any":
+		properties := &jsonProperties{}
+		properties.add("@type", &jsonSchema{Type: "string"})
+		return &jsonSchema{Type: "object", Properties: properties, Required: []string{"@type"}}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return scalarSchema(md.Fields().ByName("value").Kind())
+	}
+	return nil
+}
+
+// applyJSONRules adds the keywords matching the rules of fv to the schema of a value of fd.
+func applyJSONRules(s *jsonSchema, fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) {
+	if fv == nil {
+		return
+	}
+	if fv.UuidVer != nil {
+		if uuid, err := getUUIDRegex(fv.UuidVer); err == nil {
+			s.Format, s.Pattern = "uuid", uuid
+		}
+	} else if fv.Regex != nil {
+		s.Pattern = fv.GetRegex()
+	}
+	if fv.IntGt != nil {
+		s.ExclusiveMinimum = intNumber(fv.GetIntGt())
+	}
+	if fv.IntLt != nil {
+		s.ExclusiveMaximum = intNumber(fv.GetIntLt())
+	}
+	if fv.FloatGt != nil {
+		s.ExclusiveMinimum = floatNumber(fv.GetFloatGt() - fv.GetFloatEpsilon())
+	}
+	if fv.FloatLt != nil {
+		s.ExclusiveMaximum = floatNumber(fv.GetFloatLt() + fv.GetFloatEpsilon())
+	}
+	if fv.FloatGte != nil {
+		s.Minimum = floatNumber(fv.GetFloatGte())
+	}
+	if fv.FloatLte != nil {
+		s.Maximum = floatNumber(fv.GetFloatLte())
+	}
+	if fd.Kind() == protoreflect.StringKind {
+		if fv.GetStringNotEmpty() {
+			s.MinLength = maxBound(s.MinLength, proto.Int64(1), 0)
+		}
+		s.MinLength = maxBound(s.MinLength, fv.LengthGt, 1)
+		s.MaxLength = minBound(s.MaxLength, fv.LengthLt, -1)
+		s.MinLength = maxBound(s.MinLength, fv.LengthEq, 0)
+		s.MaxLength = minBound(s.MaxLength, fv.LengthEq, 0)
+		if (fv.LengthGt != nil || fv.LengthLt != nil || fv.LengthEq != nil) && !isASCIIPattern(s.Pattern) {
+			// the rules count the bytes of the UTF-8 encoding, minLength and maxLength count characters
+			s.Comment = byteLengthComment
+			log.Printf("WARNING: field %v has length rules counting bytes, minLength and maxLength of the schema count characters and only match them for ASCII strings\n", fd.FullName())
+		}
+	}
+	if fd.Enum() != nil && fv.GetIsInEnum() {
+		s.Type = "string"
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+	}
+}
+
+const byteLengthComment = "length_gt, length_lt and length_eq count the bytes of the UTF-8 encoding, " +
+	"minLength and maxLength only match them for ASCII strings"
+
+// isASCIIPattern reports whether the strings matching a pattern are all ASCII, the pattern being anchored at both ends
+// and only matching ASCII characters.
+func isASCIIPattern(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	re = re.Simplify()
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 ||
+		re.Sub[0].Op != syntax.OpBeginText || re.Sub[len(re.Sub)-1].Op != syntax.OpEndText {
+		return false
+	}
+	return matchesASCII(re)
+}
+
+// matchesASCII reports whether re only matches ASCII characters.
+func matchesASCII(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return false
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r >= utf8.RuneSelf || (re.Flags&syntax.FoldCase != 0 && (r == 'k' || r == 'K' || r == 's' || r == 'S')) {
+				// case folding matches the Kelvin sign and the long s
+				return false
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 1; i < len(re.Rune); i += 2 {
+			if re.Rune[i] >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	for _, sub := range re.Sub {
+		if !matchesASCII(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// requiredEach returns a schema requiring each of the given fields.
+func requiredEach(md protoreflect.MessageDescriptor, fields []string) []*jsonSchema {
+	var schemas []*jsonSchema
+	for _, name := range jsonNames(md, fields) {
+		schemas = append(schemas, &jsonSchema{Required: []string{name}})
+	}
+	return schemas
+}
+
+// jsonNames returns the protojson names of fields of md.
+func jsonNames(md protoreflect.MessageDescriptor, fields []string) []string {
+	var names []string
+	for _, name := range fields {
+		if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+			names = append(names, fd.JSONName())
+		}
+	}
+	return names
+}
+
+// comments returns the leading comments of a descriptor in its proto file.
+func comments(d protoreflect.Descriptor) string {
+	return strings.TrimSpace(d.ParentFile().SourceLocations().ByDescriptor(d).LeadingComments)
+}
+
+func intNumber(v int64) json.Number {
+	return json.Number(strconv.FormatInt(v, 10))
+}
+
+func floatNumber(v float64) json.Number {
+	// 15 digits hide the rounding errors of the tolerance arithmetic
+	return json.Number(strconv.FormatFloat(v, 'g', 15, 64))
+}
+
+// maxBound returns the greatest of the current bound and the value v adjusted by delta.
+func maxBound(current *int64, v *int64, delta int64) *int64 {
+	if v == nil || (current != nil && *current >= *v+delta) {
+		return current
+	}
+	return proto.Int64(*v + delta)
+}
+
+// minBound returns the smallest of the current bound and the value v adjusted by delta.
+func minBound(current *int64, v *int64, delta int64) *int64 {
+	if v == nil || (current != nil && *current <= *v+delta) {
+		return current
+	}
+	return proto.Int64(*v + delta)
+}
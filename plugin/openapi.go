@@ -0,0 +1,270 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIDocument is an OpenAPI 3.1 document only holding component schemas.
+type openAPIDocument struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       openAPIInfo       `json:"info"`
+	Components openAPIComponents `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `json:"schemas"`
+}
+
+// GenerateOpenAPI generates an OpenAPI 3.1 document for each file to generate, "<dir>/<file>.openapi.json", holding
+// the component schemas of its messages and of the messages they reference, keyed by full name. With
+// opts.OpenAPIMerge, the schemas of all files are merged into the document at that path instead.
+func GenerateOpenAPI(req *plugin_go.CodeGeneratorRequest, opts Options) (*plugin_go.CodeGeneratorResponse, error) {
+	format := opts.OpenAPIFormat
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "yaml" {
+		return nil, fmt.Errorf("unknown OpenAPI format '%s'", format)
+	}
+	files, err := requestFiles(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	newGenerator := func() *jsonSchemaGenerator {
+		return &jsonSchemaGenerator{explicitItemRules: opts.ExplicitItemRules, refPrefix: "#/components/schemas/", defs: map[string]*jsonSchema{}}
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	if opts.OpenAPIMerge != "" {
+		g := newGenerator()
+		for _, fd := range files {
+			for _, md := range fileMessages(fd) {
+				g.ref(md)
+			}
+		}
+		document, err := os.ReadFile(opts.OpenAPIMerge)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := mergeOpenAPI(document, g.defs, opts.OpenAPIMerge)
+		if err != nil {
+			return nil, fmt.Errorf("unable to merge the schemas into %s: %v", opts.OpenAPIMerge, err)
+		}
+		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(path.Base(opts.OpenAPIMerge)),
+			Content: proto.String(string(merged)),
+		})
+		return resp, nil
+	}
+	for _, fd := range files {
+		g := newGenerator()
+		for _, md := range fileMessages(fd) {
+			g.ref(md)
+		}
+		content, err := openAPIContent(fd, g.defs, format)
+		if err != nil {
+			return nil, err
+		}
+		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(fd.Path(), ".proto") + ".openapi." + format),
+			Content: proto.String(string(content)),
+		})
+	}
+	return resp, nil
+}
+
+func openAPIContent(fd protoreflect.FileDescriptor, schemas map[string]*jsonSchema, format string) ([]byte, error) {
+	content, err := json.MarshalIndent(&openAPIDocument{
+		OpenAPI:    "3.1.0",
+		Info:       openAPIInfo{Title: fd.Path(), Version: "0.0.0"},
+		Components: openAPIComponents{Schemas: schemas},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return append(content, '\n'), nil
+	}
+	// JSON being valid YAML, the document is decoded to a node to keep the order of the keys.
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+	return yamlContent(&node)
+}
+
+// mergeOpenAPI merges schemas into the components of an OpenAPI document, in JSON or YAML depending on the extension
+// of its name. The keywords of the schemas override those of the schemas already in the document with the same name,
+// the other keywords of the document being kept.
+func mergeOpenAPI(document []byte, schemas map[string]*jsonSchema, name string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("not an OpenAPI document")
+	}
+	content, err := json.Marshal(schemas)
+	if err != nil {
+		return nil, err
+	}
+	var generated yaml.Node
+	if err := yaml.Unmarshal(content, &generated); err != nil {
+		return nil, err
+	}
+	blockStyle(&generated)
+	components := mappingValue(doc.Content[0], "components")
+	mergeNode(mappingValue(components, "schemas"), generated.Content[0])
+	if ext := path.Ext(name); ext == ".yaml" || ext == ".yml" {
+		return yamlContent(&doc)
+	}
+	var buf bytes.Buffer
+	writeJSONNode(&buf, doc.Content[0], "")
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value of a key of a mapping node, adding an empty mapping if there is none.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// mergeNode merges the keys of the mapping from into the mapping to. Mappings are merged recursively, the required
+// lists are joined and other values of from replace those of to.
+func mergeNode(to *yaml.Node, from *yaml.Node) {
+	if to.Kind != yaml.MappingNode {
+		*to = *from
+		return
+	}
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		key, value := from.Content[i], from.Content[i+1]
+		existing := (*yaml.Node)(nil)
+		for j := 0; j+1 < len(to.Content); j += 2 {
+			if to.Content[j].Value == key.Value {
+				existing = to.Content[j+1]
+			}
+		}
+		switch {
+		case existing == nil:
+			to.Content = append(to.Content, key, value)
+		case value.Kind == yaml.MappingNode && existing.Kind == yaml.MappingNode:
+			mergeNode(existing, value)
+		case key.Value == "required" && value.Kind == yaml.SequenceNode && existing.Kind == yaml.SequenceNode:
+			for _, name := range value.Content {
+				if !containsNode(existing.Content, name.Value) {
+					existing.Content = append(existing.Content, name)
+				}
+			}
+		default:
+			*existing = *value
+		}
+	}
+}
+
+func containsNode(nodes []*yaml.Node, value string) bool {
+	for _, n := range nodes {
+		if n.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// blockStyle drops the flow style and quotes of nodes decoded from JSON, so that they are written as regular YAML.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+func yamlContent(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSONNode writes a YAML node as indented JSON, keeping the order of the keys.
+func writeJSONNode(buf *bytes.Buffer, n *yaml.Node, indent string) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		writeJSONNode(buf, n.Content[0], indent)
+	case yaml.AliasNode:
+		writeJSONNode(buf, n.Alias, indent)
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, _ := json.Marshal(n.Content[i].Value)
+			buf.WriteString(indent + "  ")
+			buf.Write(key)
+			buf.WriteString(": ")
+			writeJSONNode(buf, n.Content[i+1], indent+"  ")
+			if i+2 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i, item := range n.Content {
+			buf.WriteString(indent + "  ")
+			writeJSONNode(buf, item, indent+"  ")
+			if i+1 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+	default:
+		switch n.ShortTag() {
+		case "!!int", "!!float", "!!bool":
+			buf.WriteString(n.Value)
+		case "!!null":
+			buf.WriteString("null")
+		default:
+			value, _ := json.Marshal(n.Value)
+			buf.Write(value)
+		}
+	}
+}
@@ -43,6 +43,7 @@ and the following test code:
 package plugin
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"reflect"
@@ -54,27 +55,68 @@ import (
 	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
 	"github.com/gogo/protobuf/vanity"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	validator "github.com/monstrum/go-proto-validators"
 )
 
-const uuidPattern = "^([a-fA-F0-9]{8}-" +
-	"[a-fA-F0-9]{4}-" +
-	"[%s][a-fA-F0-9]{3}-" +
-	"[8|9|aA|bB][a-fA-F0-9]{3}-" +
-	"[a-fA-F0-9]{12})?$"
+const anyTypeName = ".google.protobuf.Any"
+const fieldMaskTypeName = ".google.protobuf.FieldMask"
 
 type plugin struct {
 	*generator.Generator
 	generator.PluginImports
-	regexPkg      generator.Single
-	fmtPkg        generator.Single
-	validatorPkg  generator.Single
-	useGogoImport bool
+	regexPkg          generator.Single
+	fmtPkg            generator.Single
+	contextPkg        generator.Single
+	validatorPkg      generator.Single
+	fieldMaskPkg      generator.Single
+	typePkgs          map[generator.GoImportPath]generator.Single
+	useGogoImport     bool
+	explicitItemRules bool
+	files             *protoregistry.Files
+	ruleSetIndex      int
+	// proto3Optionals are the proto3 optional fields of the file being generated, in synthetic oneofs which are not
+	// oneofs of the generated code.
+	proto3Optionals map[*descriptor.FieldDescriptorProto]bool
+	// usesGroups is set when the validation of a message checks the groups of the options of its context.
+	usesGroups bool
+	// usesMask is set when the validation of a message checks the mask of its context.
+	usesMask bool
+	// inRepeatedLoop is set while generating the checks of each element of a repeated field, whose violations are
+	// indexed.
+	inRepeatedLoop bool
+}
+
+// Options configures the code generated by the validator plugin.
+type Options struct {
+	// UseGogoImport generates code against gogo/protobuf instead of golang/protobuf.
+	UseGogoImport bool
+	// ExplicitItemRules fails the generation of the repeated fields setting element rules without validator.items. By
+	// default their rules are applied to each element with a deprecation warning, as versions predating
+	// validator.items did, see validator.ImplicitItemRules.
+	ExplicitItemRules bool
+	// OpenAPIFormat is the format of the documents generated by GenerateOpenAPI, "json" (the default) or "yaml".
+	OpenAPIFormat string
+	// OpenAPIMerge is the path of an existing OpenAPI document GenerateOpenAPI merges the schemas into.
+	OpenAPIMerge string
+	// DocFormat is the format of the documentation generated by GenerateDoc, "markdown" (the default) or "html".
+	DocFormat string
+	// SQLNaming is the naming of the columns of the fields in GenerateSQL, "snake" (the default), "proto" or "json".
+	SQLNaming string
+	// SQLEnums is the format of the enum columns in GenerateSQL, "number" (the default) or "name".
+	SQLEnums string
 }
 
 func NewPlugin(useGogoImport bool) generator.Plugin {
-	return &plugin{useGogoImport: useGogoImport}
+	return NewPluginWithOptions(Options{UseGogoImport: useGogoImport})
+}
+
+func NewPluginWithOptions(opts Options) generator.Plugin {
+	return &plugin{useGogoImport: opts.UseGogoImport, explicitItemRules: opts.ExplicitItemRules}
 }
 
 func (p *plugin) Name() string {
@@ -92,55 +134,206 @@ func (p *plugin) Generate(file *generator.FileDescriptor) {
 	p.PluginImports = generator.NewPluginImports(p.Generator)
 	p.regexPkg = p.NewImport("regexp")
 	p.fmtPkg = p.NewImport("fmt")
+	p.contextPkg = p.NewImport("context")
 	p.validatorPkg = p.NewImport("github.com/monstrum/go-proto-validators")
+	p.fieldMaskPkg = p.NewImport("google.golang.org/protobuf/types/known/fieldmaskpb")
+	p.typePkgs = map[generator.GoImportPath]generator.Single{}
+	p.proto3Optionals = p.proto3OptionalFields(file)
 
+	var registered []*generator.Descriptor
 	for _, msg := range file.Messages() {
 		if msg.DescriptorProto.GetOptions().GetMapEntry() {
 			continue
 		}
+		if getMessageValidatorIfAny(msg.DescriptorProto).GetDisabled() {
+			continue
+		}
 		p.generateRegexVars(file, msg)
+		p.generateCELVars(file, msg)
 		if gogoproto.IsProto3(file.FileDescriptorProto) {
 			p.generateProto3Message(file, msg)
 		} else {
 			p.generateProto2Message(file, msg)
 		}
+		registered = append(registered, msg)
+	}
+	p.generateRegistration(file, registered)
+}
+
+// generateRegistration registers the validators of the messages of a file in an init function.
+func (p *plugin) generateRegistration(file *generator.FileDescriptor, messages []*generator.Descriptor) {
+	if len(messages) == 0 {
+		return
 	}
+	p.P(`func init() {`)
+	p.In()
+	for _, message := range messages {
+		ccTypeName := generator.CamelCaseSlice(message.TypeName())
+		p.P(p.validatorPkg.Use(), `.RegisterValidator("`, messageFullName(file, message), `", func() interface{} { return &`, ccTypeName, `{} })`)
+	}
+	p.Out()
+	p.P(`}`)
+}
+
+// messageFullName returns the full name of a message type, such as "acme.users.v1.User".
+func messageFullName(file *generator.FileDescriptor, message *generator.Descriptor) string {
+	name := strings.Join(message.TypeName(), ".")
+	if file.GetPackage() != "" {
+		name = file.GetPackage() + "." + name
+	}
+	return name
 }
 
 func getFieldValidatorIfAny(field *descriptor.FieldDescriptorProto) *validator.FieldValidator {
 	if field.Options != nil {
-		v, err := proto.GetExtension(field.Options, &proto.ExtensionDesc{
-			ExtendedType:  validator.E_Field.ExtendedType,
-			ExtensionType: validator.E_Field.ExtensionType,
-			Field:         validator.E_Field.Field,
-			Name:          validator.E_Field.Name,
-			Tag:           validator.E_Field.Tag,
-			Filename:      validator.E_Field.Filename,
-		})
-		if err == nil && v.(*validator.FieldValidator) != nil {
-			return v.(*validator.FieldValidator)
+		if v, ok := getValidatorExtension(field.Options, &descriptorpb.FieldOptions{}, validator.E_Field).(*validator.FieldValidator); ok {
+			return v
 		}
 	}
 	return nil
 }
 
+// nestedValidatorCall returns the call validating the message of a field with the options of the context, restricted
+// to the fields listed under it in the mask of the context.
+func (p *plugin) nestedValidatorCall(field *descriptor.FieldDescriptorProto, variableName string) string {
+	return p.validatorPkg.Use() + ".CallContextValidatorsIfExists(mask.Context(ctx, " + strconv.Quote(field.GetName()) + "), " + variableName + ")"
+}
+
+// fieldRuleSet is one of the validators of a field: the validator set on the field itself or one of its group_rules.
+type fieldRuleSet struct {
+	// index is 0 for the validator of the field and i+1 for group_rules[i].
+	index  int
+	groups []string
+	fv     *validator.FieldValidator
+}
+
+// fieldRuleSets lists the validators of a field, only those of the default group unless generating the functions
+// validating groups.
+func (p *plugin) fieldRuleSets(field *descriptor.FieldDescriptorProto, withGroups bool) []fieldRuleSet {
+	fv := getFieldValidatorIfAny(field)
+	if fv == nil {
+		return nil
+	}
+	sets := []fieldRuleSet{{index: 0, groups: fv.GetGroups(), fv: fv}}
+	for i, groupRules := range fv.GetGroupRules() {
+		if len(groupRules.GetGroupRules()) > 0 {
+			log.Printf("WARNING: field %v has nested validator.group_rules, they have no effect\n", field.GetName())
+		}
+		sets = append(sets, fieldRuleSet{index: i + 1, groups: groupRules.GetGroups(), fv: groupRules})
+	}
+	for i := range sets {
+		rules := protov2.Clone(sets[i].fv).(*validator.FieldValidator)
+		rules.Groups, rules.GroupRules = nil, nil
+		if rules.Items != nil && rules.Items.Severity == nil {
+			rules.Items.Severity = rules.Severity
+		}
+		sets[i].fv = rules
+	}
+	if withGroups {
+		return sets
+	}
+	defaultSets := sets[:0]
+	for _, set := range sets {
+		if validator.InGroups(nil, set.groups...) {
+			defaultSets = append(defaultSets, set)
+		}
+	}
+	return defaultSets
+}
+
+// forEachFieldRuleSet calls generate for each validator of a field within the condition on its groups, nested messages
+// being validated once whatever the groups.
+func (p *plugin) forEachFieldRuleSet(field *descriptor.FieldDescriptorProto, generate func(rules fieldRuleSet, nested bool)) {
+	// google.protobuf.Any has no validator of its own, its rules are part of the rule sets.
+	nested := field.IsMessage() && !p.skipsNestedValidation(field) && !p.isAny(field)
+	p.generateInMask([]string{field.GetName()}, func() {
+		for _, rules := range p.fieldRuleSets(field, true) {
+			if validator.ValueRules(rules.fv) == nil {
+				continue
+			}
+			p.generateInGroups(rules.groups)
+			generate(rules, false)
+			p.generateInGroupsEnd()
+		}
+		if nested {
+			generate(fieldRuleSet{}, true)
+		}
+	})
+}
+
+// generateInMask generates the checks of generate within a condition on the fields names being listed in the mask of
+// the context, if there are any.
+func (p *plugin) generateInMask(names []string, generate func()) {
+	out := p.Generator.Buffer
+	p.Generator.Buffer = new(bytes.Buffer)
+	p.In()
+	generate()
+	p.Out()
+	checks := p.Generator.Buffer
+	p.Generator.Buffer = out
+	if checks.Len() == 0 {
+		return
+	}
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, strconv.Quote(name))
+	}
+	p.usesMask = true
+	p.P(`if mask.Lists(`, strings.Join(args, ", "), `) {`)
+	p.Write(checks.Bytes())
+	p.P(`}`)
+}
+
+// generateInGroups opens a condition on the groups of the options of the context.
+func (p *plugin) generateInGroups(ruleGroups []string) {
+	args := []string{"groups"}
+	for _, group := range ruleGroups {
+		args = append(args, strconv.Quote(group))
+	}
+	p.usesGroups = true
+	p.P(`if `, p.validatorPkg.Use(), `.InGroups(`, strings.Join(args, ", "), `) {`)
+	p.In()
+}
+
+func (p *plugin) generateInGroupsEnd() {
+	p.Out()
+	p.P(`}`)
+}
+
 func getOneOfValidatorIfAny(oneOf *descriptor.OneofDescriptorProto) *validator.OneofValidator {
 	if oneOf.Options != nil {
-		v, err := proto.GetExtension(oneOf.Options, &proto.ExtensionDesc{
-			ExtendedType:  validator.E_Oneof.ExtendedType,
-			ExtensionType: validator.E_Oneof.ExtensionType,
-			Field:         validator.E_Oneof.Field,
-			Name:          validator.E_Oneof.Name,
-			Tag:           validator.E_Oneof.Tag,
-			Filename:      validator.E_Oneof.Filename,
-		})
-		if err == nil && v.(*validator.OneofValidator) != nil {
-			return v.(*validator.OneofValidator)
+		if v, ok := getValidatorExtension(oneOf.Options, &descriptorpb.OneofOptions{}, validator.E_Oneof).(*validator.OneofValidator); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func getMessageValidatorIfAny(message *descriptor.DescriptorProto) *validator.MessageValidator {
+	if message.Options != nil {
+		if v, ok := getValidatorExtension(message.Options, &descriptorpb.MessageOptions{}, validator.E_Message).(*validator.MessageValidator); ok {
+			return v
 		}
 	}
 	return nil
 }
 
+// getValidatorExtension decodes a validator extension out of gogo descriptor options. The validator messages are
+// generated with protoc-gen-go, so the options are re-encoded into their APIv2 counterpart before being read.
+func getValidatorExtension(options proto.Message, dst protov2.Message, xt protoreflect.ExtensionType) interface{} {
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return nil
+	}
+	if err := protov2.Unmarshal(data, dst); err != nil {
+		return nil
+	}
+	if !protov2.HasExtension(dst, xt) {
+		return nil
+	}
+	return protov2.GetExtension(dst, xt)
+}
+
 func (p *plugin) isSupportedInt(field *descriptor.FieldDescriptorProto) bool {
 	switch *(field.Type) {
 	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_INT64:
@@ -168,8 +361,12 @@ func (p *plugin) isSupportedFloat(field *descriptor.FieldDescriptorProto) bool {
 func (p *plugin) generateRegexVars(_ *generator.FileDescriptor, message *generator.Descriptor) {
 	ccTypeName := generator.CamelCaseSlice(message.TypeName())
 	for _, field := range message.Field {
-		fieldValidator := getFieldValidatorIfAny(field)
-		if fieldValidator != nil {
+		for _, rules := range p.fieldRuleSets(field, true) {
+			p.ruleSetIndex = rules.index
+			fieldValidator := rules.fv
+			if field.IsRepeated() && fieldValidator.GetItems() != nil {
+				fieldValidator = fieldValidator.GetItems()
+			}
 			fieldName := p.GetOneOfFieldName(message, field)
 			if fieldValidator.Regex != nil && fieldValidator.UuidVer != nil {
 				log.Printf("WARNING: regex and uuid validator is set for field %v.%v, only one of them can be set. Regex and UUID validator is ignored for this field.", ccTypeName, fieldName)
@@ -186,14 +383,37 @@ func (p *plugin) generateRegexVars(_ *generator.FileDescriptor, message *generat
 			}
 		}
 	}
+	p.ruleSetIndex = 0
+}
+
+// proto3OptionalFields lists the proto3 optional fields of a file, which gogo's descriptors only see as oneof fields.
+func (p *plugin) proto3OptionalFields(file *generator.FileDescriptor) map[*descriptor.FieldDescriptorProto]bool {
+	optionals := map[*descriptor.FieldDescriptorProto]bool{}
+	for _, message := range file.Messages() {
+		for _, field := range message.Field {
+			if field.OneofIndex == nil {
+				continue
+			}
+			fd := p.messageDescriptor(file, message).Fields().ByNumber(protoreflect.FieldNumber(field.GetNumber()))
+			if od := fd.ContainingOneof(); od != nil && od.IsSynthetic() {
+				optionals[field] = true
+			}
+		}
+	}
+	return optionals
 }
 
 func (p *plugin) GetFieldName(message *generator.Descriptor, field *descriptor.FieldDescriptorProto) string {
+	if p.proto3Optionals[field] {
+		// proto3 optional fields are named after themselves rather than their synthetic oneof
+		return p.GetOneOfFieldName(message, field)
+	}
 	fieldName := p.Generator.GetFieldName(message, field)
 	if p.useGogoImport {
 		return fieldName
 	}
-	if gogoproto.IsEmbed(field) {
+	// golang/protobuf ignores gogoproto.embed and gogoproto.customname
+	if gogoproto.IsEmbed(field) || gogoproto.GetCustomName(field) != "" {
 		fieldName = generator.CamelCase(*field.Name)
 	}
 	return fieldName
@@ -204,265 +424,431 @@ func (p *plugin) GetOneOfFieldName(message *generator.Descriptor, field *descrip
 	if p.useGogoImport {
 		return fieldName
 	}
-	if gogoproto.IsEmbed(field) {
+	// golang/protobuf ignores gogoproto.embed and gogoproto.customname
+	if gogoproto.IsEmbed(field) || gogoproto.GetCustomName(field) != "" {
 		fieldName = generator.CamelCase(*field.Name)
 	}
 	return fieldName
 }
 
 func (p *plugin) generateProto2Message(file *generator.FileDescriptor, message *generator.Descriptor) {
-	p.generateProto2MessageValidateFunc(file, message)
-	p.generateProto2MessageValidateAllFunc(file, message)
+	p.generateValidateFuncs(message, func() {
+		p.generateProto2ValidateFunctions(file, message)
+		p.generateCELValidator(file, message)
+	})
+	p.generateMaskFunc(message)
+	p.generateTransitionFunc(file, message)
+	p.generateRulesFunc(message)
 }
 
-func (p *plugin) generateProto2ValidateFunctions(file *generator.FileDescriptor, message *generator.Descriptor, assignInsteadReturn bool) {
-	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+func (p *plugin) generateProto2ValidateFunctions(file *generator.FileDescriptor, message *generator.Descriptor) {
+	p.generateMessageValidator(file, message)
+	p.generateConditionalValidators(file, message)
 
 	for _, field := range message.Field {
-		fieldName := p.GetFieldName(message, field)
-		fieldValidator := getFieldValidatorIfAny(field)
-		if fieldValidator == nil && !field.IsMessage() {
-			continue
+		p.forEachFieldRuleSet(field, func(rules fieldRuleSet, nested bool) {
+			p.generateProto2FieldValidator(file, message, field, rules, nested)
+		})
+	}
+}
+
+func (p *plugin) generateProto2FieldValidator(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto, rules fieldRuleSet, nested bool) {
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	p.ruleSetIndex = rules.index
+	fieldName := p.GetFieldName(message, field)
+	fieldValidator := validator.ValueRules(rules.fv)
+	if fieldValidator == nil && !nested {
+		return
+	}
+	if p.validatorWithMessageExists(fieldValidator) {
+		log.Printf("WARNING: field %v.%v is a proto2 message, validator.msg_exists has no effect\n", ccTypeName, fieldName)
+	}
+	if p.validatorWithAnyConstraint(fieldValidator) && !p.isAny(field) {
+		log.Printf("WARNING: field %v.%v is not a google.protobuf.Any, validator.any_* have no effect\n", ccTypeName, fieldName)
+	}
+	variableName := "this." + fieldName
+	repeated := field.IsRepeated()
+	// Golang's generator ignores gogoproto.nullable, message fields are always pointers
+	nullable := (gogoproto.IsNullable(field) || (field.IsMessage() && !gogoproto.ImportsGoGoProto(file.FileDescriptorProto))) && !(p.useGogoImport && gogoproto.IsEmbed(field))
+	// For proto2 syntax, only Gogo generates non-pointer fields
+	nonPointer := gogoproto.ImportsGoGoProto(file.FileDescriptorProto) && !gogoproto.IsNullable(field)
+	valueValidator := fieldValidator
+	if repeated {
+		valueValidator = p.itemValidator(field, ccTypeName, fieldName, fieldValidator)
+	}
+	// google.protobuf.Any rules are generated along with the nested validation.
+	nested = nested || (field.IsMessage() && p.isAny(field) && p.validatorWithAnyConstraint(valueValidator))
+	if valueValidator.GetFieldMaskTarget() != "" && !p.isFieldMask(field) {
+		log.Printf("WARNING: field %v.%v is not a google.protobuf.FieldMask, validator.field_mask_target has no effect\n", ccTypeName, fieldName)
+	}
+	if repeated {
+		p.generateRepeatedCountValidator(variableName, ccTypeName, fieldName, fieldValidator)
+		p.generateUniqueValidator(field, variableName, ccTypeName, fieldName, fieldValidator)
+		if !p.isImplicitItemValidator(field, fieldValidator) {
+			p.generateLengthValidator(variableName, ccTypeName, fieldName, fieldValidator)
 		}
-		if p.validatorWithMessageExists(fieldValidator) {
-			log.Printf("WARNING: field %v.%v is a proto2 message, validator.msg_exists has no effect\n", ccTypeName, fieldName)
+		if nested || p.validatorWithNonRepeatedConstraint(valueValidator) {
+			p.generateRepeatedLoop(variableName)
+			variableName = "item"
+		}
+	} else if nullable {
+		p.P(`if `, variableName, ` != nil {`)
+		p.In()
+		if !field.IsBytes() {
+			variableName = "*(" + variableName + ")"
+		}
+	} else if nonPointer {
+		// can use the field directly
+	} else if !field.IsMessage() {
+		variableName = `this.Get` + fieldName + `()`
+	}
+	if !repeated && fieldValidator != nil {
+		if fieldValidator.RepeatedCountMin != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.min_elts has no effects\n", ccTypeName, fieldName)
 		}
-		variableName := "this." + fieldName
-		repeated := field.IsRepeated()
-		nullable := gogoproto.IsNullable(field) && !(p.useGogoImport && gogoproto.IsEmbed(field))
-		// For proto2 syntax, only Gogo generates non-pointer fields
-		nonPointer := gogoproto.ImportsGoGoProto(file.FileDescriptorProto) && !gogoproto.IsNullable(field)
+		if fieldValidator.RepeatedCountMax != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.max_elts has no effects\n", ccTypeName, fieldName)
+		}
+		if fieldValidator.Unique != nil || fieldValidator.UniqueBy != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.unique has no effects\n", ccTypeName, fieldName)
+		}
+		if fieldValidator.Items != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.items has no effects\n", ccTypeName, fieldName)
+		}
+	}
+	if field.IsString() {
+		p.generateStringValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if p.isSupportedInt(field) {
+		p.generateIntValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsEnum() {
+		p.generateEnumValidator(field, variableName, ccTypeName, fieldName, valueValidator)
+	} else if p.isSupportedFloat(field) {
+		p.generateFloatValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsBytes() {
+		p.generateLengthValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsRequired() && !field.IsMessage() {
+		p.generateRequiredValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if p.isFieldMask(field) && valueValidator.GetFieldMaskTarget() != "" {
+		if repeated && nullable {
+			variableName = "*(item)"
+		}
+		p.generateFieldMaskValidator(message, "&("+variableName+")", ccTypeName, fieldName, valueValidator)
+	} else if nested {
+		if repeated && nullable {
+			variableName = "*(item)"
+		}
+		violation := "message"
 		if repeated {
-			p.generateRepeatedCountValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-			if field.IsMessage() || p.validatorWithNonRepeatedConstraint(fieldValidator) {
-				if assignInsteadReturn && field.IsMessage() {
-					p.P(`for i, item := range `, variableName, `{`)
-				} else {
-					p.P(`for _, item := range `, variableName, `{`)
-				}
-				p.In()
-				variableName = "item"
-			}
-		} else if nullable {
-			p.P(`if `, variableName, ` != nil {`)
-			p.In()
-			if !field.IsBytes() {
-				variableName = "*(" + variableName + ")"
-			}
-		} else if nonPointer {
-			// can use the field directly
-		} else if !field.IsMessage() {
-			variableName = `this.Get` + fieldName + `()`
-		}
-		if !repeated && fieldValidator != nil {
-			if fieldValidator.RepeatedCountMin != nil {
-				log.Printf("WARNING: field %v.%v is not repeated, validator.min_elts has no effects\n", ccTypeName, fieldName)
-			}
-			if fieldValidator.RepeatedCountMax != nil {
-				log.Printf("WARNING: field %v.%v is not repeated, validator.max_elts has no effects\n", ccTypeName, fieldName)
-			}
+			violation = "array"
 		}
-		if field.IsString() {
-			p.generateStringValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if p.isSupportedInt(field) {
-			p.generateIntValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsEnum() {
-			p.generateEnumValidator(field, variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if p.isSupportedFloat(field) {
-			p.generateFloatValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsBytes() {
-			p.generateLengthValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsRequired() {
-			p.generateRequiredValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsMessage() {
-			if repeated && nullable {
-				variableName = "*(item)"
-			}
-			if assignInsteadReturn {
-				p.P(`if err := `, p.validatorPkg.Use(), `.CallValidatorsIfExists(&(`, variableName, `)); err != nil {`)
-			} else {
-				p.P(`if err := `, p.validatorPkg.Use(), `.CallValidatorIfExists(&(`, variableName, `)); err != nil {`)
-			}
+		if p.isAny(field) && p.validatorWithAnyConstraint(valueValidator) {
+			p.generateAnyValidator("&("+variableName+")", ccTypeName, fieldName, violation, valueValidator)
+		} else {
+			p.P(`if err := `, p.nestedValidatorCall(field, "&("+variableName+")"), `; err != nil {`)
 			p.In()
 
-			violation := "message"
-			if repeated {
-				violation = "array"
-			}
-
-			p.generateErrorFromErr(variableName, fieldName, violation, assignInsteadReturn)
+			p.generateErrorFromErr(variableName, fieldName, violation)
 			p.Out()
 			p.P(`}`)
 		}
-		if repeated {
-			// end the repeated loop
-			if field.IsMessage() || p.validatorWithNonRepeatedConstraint(fieldValidator) {
-				// This internal 'if' cannot be refactored as it would change semantics with respect to the corresponding prelude 'if's
-				p.Out()
-				p.P(`}`)
-			}
-		} else if nullable {
-			// end indent if around nullable
-			p.Out()
-			p.P(`}`)
+	}
+	if repeated {
+		// end the repeated loop
+		if nested || p.validatorWithNonRepeatedConstraint(valueValidator) {
+			// This internal 'if' cannot be refactored as it would change semantics with respect to the corresponding prelude 'if's
+			p.endRepeatedLoop()
 		}
+	} else if nullable {
+		// end indent if around nullable
+		p.Out()
+		p.P(`}`)
 	}
 }
 
-func (p *plugin) generateProto2MessageValidateAllFunc(file *generator.FileDescriptor, message *generator.Descriptor) {
-	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+func (p *plugin) generateProto3Message(file *generator.FileDescriptor, message *generator.Descriptor) {
+	p.generateValidateFuncs(message, func() {
+		p.generateValidateFunctions(file, message)
+		p.generateCELValidator(file, message)
+	})
+	p.generateMaskFunc(message)
+	p.generateTransitionFunc(file, message)
+	p.generateRulesFunc(message)
+}
 
-	p.P(`func (this *`, ccTypeName, `) ValidateAll() error {`)
+// generateRepeatedLoop opens the loop over the elements of a repeated field, which stops on the limits of the
+// options of the context.
+func (p *plugin) generateRepeatedLoop(variableName string) {
+	p.P(`for i, item := range `, variableName, `{`)
 	p.In()
-	p.P(`validations := &`, p.validatorPkg.Use(), `.ValidationErrors{}`)
-
-	p.generateProto2ValidateFunctions(file, message, true)
-
-	p.P(`if validations.IsError() {`)
+	p.P(`if !validations.Inspect(i) {`)
 	p.In()
-	p.P(`return `, `validations`)
+	p.P(`break`)
 	p.Out()
 	p.P(`}`)
+	p.inRepeatedLoop = true
+}
 
-	p.P(`return nil`)
+// endRepeatedLoop ends the loop started by generateRepeatedLoop.
+func (p *plugin) endRepeatedLoop() {
 	p.Out()
 	p.P(`}`)
+	p.inRepeatedLoop = false
 }
 
-func (p *plugin) generateProto2MessageValidateFunc(file *generator.FileDescriptor, message *generator.Descriptor) {
+// generateValidateFuncs generates ValidateAllContextWithWarnings, collecting the violations of the message with the
+// options of its context as generated by validateAll, and the validation methods wrapping it.
+func (p *plugin) generateValidateFuncs(message *generator.Descriptor, validateAll func()) {
 	ccTypeName := generator.CamelCaseSlice(message.TypeName())
-
 	p.P(`func (this *`, ccTypeName, `) Validate() error {`)
 	p.In()
-
-	p.generateProto2ValidateFunctions(file, message, false)
-
-	p.P(`return nil`)
+	p.P(`return this.ValidateGroups()`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateGroups(groups ...string) error {`)
+	p.In()
+	p.P(`return `, p.validatorPkg.Use(), `.ValidateFirst(this, groups...)`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateAll() error {`)
+	p.In()
+	p.P(`return this.ValidateAllWithWarnings().Err()`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateAllWithWarnings() *`, p.validatorPkg.Use(), `.ValidationErrors {`)
+	p.In()
+	p.P(`validations, _ := this.ValidateAllContextWithWarnings(`, p.contextPkg.Use(), `.Background())`)
+	p.P(`return validations`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateAllGroups(groups ...string) error {`)
+	p.In()
+	p.P(`return this.ValidateAllContext(`, p.validatorPkg.Use(), `.WithOptions(`, p.contextPkg.Use(), `.Background(), `, p.validatorPkg.Use(), `.Groups(groups...)))`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateContext(ctx `, p.contextPkg.Use(), `.Context, opts ...`, p.validatorPkg.Use(), `.Option) error {`)
+	p.In()
+	p.P(`return `, p.validatorPkg.Use(), `.ValidateContext(ctx, this, opts...)`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateAllContext(ctx `, p.contextPkg.Use(), `.Context) error {`)
+	p.In()
+	p.P(`validations, err := this.ValidateAllContextWithWarnings(ctx)`)
+	p.P(`if err != nil {`)
+	p.In()
+	p.P(`return err`)
+	p.Out()
+	p.P(`}`)
+	p.P(`return validations.Err()`)
+	p.Out()
+	p.P(`}`)
+	p.P(`func (this *`, ccTypeName, `) ValidateAllContextWithWarnings(ctx `, p.contextPkg.Use(), `.Context) (*`, p.validatorPkg.Use(), `.ValidationErrors, error) {`)
+	p.In()
+	p.generateContextErr()
+	p.P(`validations := `, p.validatorPkg.Use(), `.NewValidationErrors(ctx)`)
+	// The groups are only read when the rules depend on them, the rules are generated first to find out.
+	out := p.Generator.Buffer
+	p.Generator.Buffer = new(bytes.Buffer)
+	p.usesGroups, p.usesMask = false, false
+	validateAll()
+	rules := p.Generator.Buffer
+	p.Generator.Buffer = out
+	if p.usesGroups {
+		p.P(`groups := `, p.validatorPkg.Use(), `.OptionsFromContext(ctx).Groups`)
+	}
+	if p.usesMask {
+		p.P(`mask := `, p.validatorPkg.Use(), `.MaskFromContext(ctx)`)
+	}
+	p.Write(rules.Bytes())
+	p.generateContextErr()
+	p.P(`return validations.Complete(), nil`)
 	p.Out()
 	p.P(`}`)
 }
 
-func (p *plugin) generateProto3Message(file *generator.FileDescriptor, message *generator.Descriptor) {
-	p.generateMessageValidateFunc(file, message)
-	p.generateMessageValidateAllFunc(file, message)
+func (p *plugin) generateContextErr() {
+	p.P(`if err := ctx.Err(); err != nil {`)
+	p.In()
+	p.P(`return nil, err`)
+	p.Out()
+	p.P(`}`)
 }
 
-func (p *plugin) generateMessageValidateAllFunc(file *generator.FileDescriptor, message *generator.Descriptor) {
+func (p *plugin) generateMaskFunc(message *generator.Descriptor) {
 	ccTypeName := generator.CamelCaseSlice(message.TypeName())
-	p.P(`func (this *`, ccTypeName, `) ValidateAll() error {`)
+	p.P(`func (this *`, ccTypeName, `) ValidateMask(mask *`, p.fieldMaskPkg.Use(), `.FieldMask) error {`)
 	p.In()
-	p.P(`validations := &`, p.validatorPkg.Use(), `.ValidationErrors{}`)
-
-	p.generateValidateFunctions(file, message, true)
-
-	p.P(`if validations.IsError() {`)
+	p.P(`ctx, err := `, p.validatorPkg.Use(), `.WithMask(`, p.contextPkg.Use(), `.Background(), this, mask.GetPaths())`)
+	p.P(`if err != nil {`)
 	p.In()
-	p.P(`return `, `validations`)
+	p.P(`return err`)
 	p.Out()
 	p.P(`}`)
-
-	p.P(`return nil`)
+	p.P(`return this.ValidateAllContext(ctx)`)
 	p.Out()
 	p.P(`}`)
 }
 
-func (p *plugin) generateMessageValidateFunc(file *generator.FileDescriptor, message *generator.Descriptor) {
+// generateRulesFunc generates the ValidationRules method, exposing the rules of the message as data.
+func (p *plugin) generateRulesFunc(message *generator.Descriptor) {
 	ccTypeName := generator.CamelCaseSlice(message.TypeName())
-	p.P(`func (this *`, ccTypeName, `) Validate() error {`)
+	p.P(`func (this *`, ccTypeName, `) ValidationRules() *`, p.validatorPkg.Use(), `.Rules {`)
 	p.In()
-
-	p.generateValidateFunctions(file, message, false)
-
-	p.P(`return nil`)
+	p.P(`return `, p.validatorPkg.Use(), `.MessageRules(this)`)
 	p.Out()
 	p.P(`}`)
 }
 
-func (p *plugin) generateValidateFunctions(file *generator.FileDescriptor, message *generator.Descriptor, assignInsteadReturn bool) {
-	ccTypeName := generator.CamelCaseSlice(message.TypeName())
-	for _, oneOf := range message.OneofDecl {
+func (p *plugin) generateValidateFunctions(file *generator.FileDescriptor, message *generator.Descriptor) {
+	p.generateMessageValidator(file, message)
+	p.generateConditionalValidators(file, message)
+	for i, oneOf := range message.OneofDecl {
 		oneOfValidator := getOneOfValidatorIfAny(oneOf)
 		if oneOfValidator == nil {
 			continue
 		}
 		if oneOfValidator.GetRequired() {
 			oneOfName := generator.CamelCase(oneOf.GetName())
-			p.P(`if this.Get` + oneOfName + `() == nil {`)
-			p.In()
-			p.generateErrorString(oneOfName, oneOfName, "one_of", "one of the fields must be set", nil, assignInsteadReturn)
-			p.Out()
-			p.P(`}`)
+			p.generateInMask(oneofFieldNames(message, i), func() {
+				p.generateInGroups(nil)
+				p.P(`if this.Get` + oneOfName + `() == nil {`)
+				p.In()
+				p.generateMessageErrorString(oneOfName, "one_of", validator.OneofRequiredMessage)
+				p.Out()
+				p.P(`}`)
+				p.generateInGroupsEnd()
+			})
 		}
 	}
 	for _, field := range message.Field {
-		fieldValidator := getFieldValidatorIfAny(field)
-		if fieldValidator == nil && !field.IsMessage() {
-			continue
+		p.forEachFieldRuleSet(field, func(rules fieldRuleSet, nested bool) {
+			p.generateFieldValidator(file, message, field, rules, nested)
+		})
+	}
+}
+
+// oneofFieldNames returns the names of the fields of the oneof i of message.
+func oneofFieldNames(message *generator.Descriptor, i int) []string {
+	var names []string
+	for _, field := range message.Field {
+		if field.OneofIndex != nil && int(field.GetOneofIndex()) == i {
+			names = append(names, field.GetName())
 		}
-		isOneOf := field.OneofIndex != nil
-		fieldName := p.GetOneOfFieldName(message, field)
-		variableName := "this." + fieldName
-		repeated := field.IsRepeated()
-		// Golang's proto3 has no concept of unset primitive fields
-		nullable := (gogoproto.IsNullable(field) || !gogoproto.ImportsGoGoProto(file.FileDescriptorProto)) && field.IsMessage() && !(p.useGogoImport && gogoproto.IsEmbed(field))
-		if p.fieldIsProto3Map(file, message, field) {
-			p.P(`// Validation of proto3 map<> fields is unsupported.`)
-			continue
+	}
+	return names
+}
+
+func (p *plugin) generateFieldValidator(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto, rules fieldRuleSet, nested bool) {
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	p.ruleSetIndex = rules.index
+	fieldValidator := validator.ValueRules(rules.fv)
+	if fieldValidator == nil && !nested {
+		return
+	}
+	isOneOf := field.OneofIndex != nil && !p.proto3Optionals[field]
+	// proto3 optional scalars are pointers like proto2 ones
+	optional := p.proto3Optionals[field] && !field.IsMessage()
+	fieldName := p.GetOneOfFieldName(message, field)
+	if p.validatorWithAnyConstraint(fieldValidator) && !p.isAny(field) {
+		log.Printf("WARNING: field %v.%v is not a google.protobuf.Any, validator.any_* have no effect\n", ccTypeName, fieldName)
+	}
+	variableName := "this." + fieldName
+	repeated := field.IsRepeated()
+	// Golang's proto3 has no concept of unset primitive fields
+	nullable := (gogoproto.IsNullable(field) || !gogoproto.ImportsGoGoProto(file.FileDescriptorProto)) && field.IsMessage() && !(p.useGogoImport && gogoproto.IsEmbed(field))
+	if p.fieldIsProto3Map(file, message, field) {
+		p.P(`// Validation of proto3 map<> fields is unsupported.`)
+		return
+	}
+	if isOneOf {
+		p.In()
+		oneOfName := p.GetFieldName(message, field)
+		oneOfType := p.OneOfTypeName(message, field)
+		// if x, ok := m.GetType().(*OneOfMessage3_OneInt); ok {
+		p.P(`if oneOfNester, ok := this.Get` + oneOfName + `().(* ` + oneOfType + `); ok {`)
+		variableName = "oneOfNester." + p.GetOneOfFieldName(message, field)
+	}
+	if optional {
+		p.P(`if `, variableName, ` != nil {`)
+		p.In()
+		if !field.IsBytes() {
+			variableName = "*(" + variableName + ")"
 		}
-		if isOneOf {
-			p.In()
-			oneOfName := p.GetFieldName(message, field)
-			oneOfType := p.OneOfTypeName(message, field)
-			// if x, ok := m.GetType().(*OneOfMessage3_OneInt); ok {
-			p.P(`if oneOfNester, ok := this.Get` + oneOfName + `().(* ` + oneOfType + `); ok {`)
-			variableName = "oneOfNester." + p.GetOneOfFieldName(message, field)
+	}
+	valueValidator := fieldValidator
+	if repeated {
+		valueValidator = p.itemValidator(field, ccTypeName, fieldName, fieldValidator)
+	}
+	// google.protobuf.Any rules are generated along with the nested validation.
+	nested = nested || (field.IsMessage() && p.isAny(field) && p.validatorWithAnyConstraint(valueValidator))
+	if valueValidator.GetFieldMaskTarget() != "" && !p.isFieldMask(field) {
+		log.Printf("WARNING: field %v.%v is not a google.protobuf.FieldMask, validator.field_mask_target has no effect\n", ccTypeName, fieldName)
+	}
+	if repeated {
+		p.generateRepeatedCountValidator(variableName, ccTypeName, fieldName, fieldValidator)
+		p.generateUniqueValidator(field, variableName, ccTypeName, fieldName, fieldValidator)
+		if !p.isImplicitItemValidator(field, fieldValidator) {
+			p.generateLengthValidator(variableName, ccTypeName, fieldName, fieldValidator)
 		}
-		if repeated {
-			p.generateRepeatedCountValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-			if field.IsMessage() || p.validatorWithNonRepeatedConstraint(fieldValidator) {
-				if assignInsteadReturn && field.IsMessage() {
-					p.P(`for i, item := range `, variableName, `{`)
-				} else {
-					p.P(`for _, item := range `, variableName, `{`)
-				}
+		if nested || p.validatorWithNonRepeatedConstraint(valueValidator) {
+			p.generateRepeatedLoop(variableName)
+			variableName = "item"
+		}
+	} else if fieldValidator != nil {
+		if fieldValidator.RepeatedCountMin != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.min_elts has no effects\n", ccTypeName, fieldName)
+		}
+		if fieldValidator.RepeatedCountMax != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.max_elts has no effects\n", ccTypeName, fieldName)
+		}
+		if fieldValidator.Unique != nil || fieldValidator.UniqueBy != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.unique has no effects\n", ccTypeName, fieldName)
+		}
+		if fieldValidator.Items != nil {
+			log.Printf("WARNING: field %v.%v is not repeated, validator.items has no effects\n", ccTypeName, fieldName)
+		}
+	}
+	if field.IsString() {
+		p.generateStringValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if p.isSupportedInt(field) {
+		p.generateIntValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsEnum() {
+		p.generateEnumValidator(field, variableName, ccTypeName, fieldName, valueValidator)
+	} else if p.isSupportedFloat(field) {
+		p.generateFloatValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsBytes() {
+		p.generateLengthValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsRequired() {
+		p.generateRequiredValidator(variableName, ccTypeName, fieldName, valueValidator)
+	} else if field.IsMessage() {
+		if p.validatorWithMessageExists(fieldValidator) {
+			if nullable && !repeated {
+				p.P(`if nil == `, variableName, `{`)
 				p.In()
-				variableName = "item"
-			}
-		} else if fieldValidator != nil {
-			if fieldValidator.RepeatedCountMin != nil {
-				log.Printf("WARNING: field %v.%v is not repeated, validator.min_elts has no effects\n", ccTypeName, fieldName)
-			}
-			if fieldValidator.RepeatedCountMax != nil {
-				log.Printf("WARNING: field %v.%v is not repeated, validator.max_elts has no effects\n", ccTypeName, fieldName)
+				p.generateMessageErrorString(fieldName, "empty", validator.MessageExistsMessage)
+				p.Out()
+				p.P(`}`)
+			} else if repeated {
+				log.Printf("WARNING: field %v.%v is repeated, validator.msg_exists has no effect\n", ccTypeName, fieldName)
+			} else if !nullable {
+				log.Printf("WARNING: field %v.%v is a nullable=false, validator.msg_exists has no effect\n", ccTypeName, fieldName)
 			}
 		}
-		if field.IsString() {
-			p.generateStringValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if p.isSupportedInt(field) {
-			p.generateIntValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsEnum() {
-			p.generateEnumValidator(field, variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if p.isSupportedFloat(field) {
-			p.generateFloatValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsBytes() {
-			p.generateLengthValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsRequired() {
-			p.generateRequiredValidator(variableName, ccTypeName, fieldName, fieldValidator, assignInsteadReturn)
-		} else if field.IsMessage() {
-			if p.validatorWithMessageExists(fieldValidator) {
-				if nullable && !repeated {
-					p.P(`if nil == `, variableName, `{`)
-					p.In()
-					p.generateErrorString(variableName, fieldName, "empty", "message must exist", nil, assignInsteadReturn)
-					p.Out()
-					p.P(`}`)
-				} else if repeated {
-					log.Printf("WARNING: field %v.%v is repeated, validator.msg_exists has no effect\n", ccTypeName, fieldName)
-				} else if !nullable {
-					log.Printf("WARNING: field %v.%v is a nullable=false, validator.msg_exists has no effect\n", ccTypeName, fieldName)
-				}
+		if repeated && nullable && valueValidator != fieldValidator && p.validatorWithMessageExists(valueValidator) {
+			p.P(`if nil == `, variableName, `{`)
+			p.In()
+			p.generateMessageErrorString(fieldName, "empty", validator.MessageExistsMessage)
+			p.Out()
+			p.P(`}`)
+		}
+		if p.isFieldMask(field) && valueValidator.GetFieldMaskTarget() != "" {
+			maskName := variableName
+			if !nullable {
+				maskName = "&(" + variableName + ")"
 			}
+			p.generateFieldMaskValidator(message, maskName, ccTypeName, fieldName, valueValidator)
+		}
 
+		if nested {
 			if nullable {
 				p.P(`if `, variableName, ` != nil {`)
 				p.In()
@@ -471,45 +857,305 @@ func (p *plugin) generateValidateFunctions(file *generator.FileDescriptor, messa
 				variableName = "&(" + variableName + ")"
 			}
 
-			if assignInsteadReturn {
-				p.P(`if err := `, p.validatorPkg.Use(), `.CallValidatorsIfExists(`, variableName, `); err != nil {`)
-			} else {
-				p.P(`if err := `, p.validatorPkg.Use(), `.CallValidatorIfExists(`, variableName, `); err != nil {`)
-			}
-			p.In()
-
 			violation := "message"
 			if repeated {
 				violation = "array"
 			}
+			if p.isAny(field) && p.validatorWithAnyConstraint(valueValidator) {
+				p.generateAnyValidator(variableName, ccTypeName, fieldName, violation, valueValidator)
+			} else {
+				p.P(`if err := `, p.nestedValidatorCall(field, variableName), `; err != nil {`)
+				p.In()
 
-			p.generateErrorFromErr(variableName, fieldName, violation, assignInsteadReturn)
-			p.Out()
-			p.P(`}`)
+				p.generateErrorFromErr(variableName, fieldName, violation)
+				p.Out()
+				p.P(`}`)
+			}
 			if nullable {
 				p.Out()
 				p.P(`}`)
 			}
 		}
-		if repeated && (field.IsMessage() || p.validatorWithNonRepeatedConstraint(fieldValidator)) {
-			// end the repeated loop
+	}
+	if repeated && (nested || p.validatorWithNonRepeatedConstraint(valueValidator)) {
+		// end the repeated loop
+		p.endRepeatedLoop()
+	}
+	if optional {
+		p.Out()
+		p.P(`}`)
+	}
+	if isOneOf {
+		// end the oneOf if statement
+		p.Out()
+		p.P(`}`)
+	}
+}
+
+func (p *plugin) generateMessageValidator(file *generator.FileDescriptor, message *generator.Descriptor) {
+	mv := getMessageValidatorIfAny(message.DescriptorProto)
+	if len(mv.GetRequired()) == 0 && len(mv.GetAtLeastOneOf()) == 0 && len(mv.GetAtMostOneOf()) == 0 {
+		return
+	}
+	p.generateInGroups(nil)
+	defer p.generateInGroupsEnd()
+	for _, name := range mv.GetRequired() {
+		field := p.messageField(message, name, "validator.message required")
+		fieldName := p.GetFieldName(message, field)
+		p.generateInMask([]string{name}, func() {
+			p.P(`if !(`, p.fieldIsSetExpr(file, message, field), `) {`)
+			p.In()
+			p.generateMessageErrorString(fieldName, "required", "value must be set")
 			p.Out()
 			p.P(`}`)
-		}
-		if isOneOf {
-			// end the oneOf if statement
+		})
+	}
+	for _, group := range mv.GetAtLeastOneOf() {
+		conditions := p.fieldGroupConditions(file, message, group, "at_least_one_of")
+		p.generateInMask(group.GetFields(), func() {
+			p.P(`if !(`, strings.Join(conditions, " || "), `) {`)
+			p.In()
+			errorStr := fmt.Sprintf("one of the fields %s must be set", strings.Join(group.GetFields(), ", "))
+			p.generateMessageErrorString(p.fieldGroupName(message, group), "at_least_one_of", errorStr)
 			p.Out()
 			p.P(`}`)
+		})
+	}
+	for i, group := range mv.GetAtMostOneOf() {
+		conditions := p.fieldGroupConditions(file, message, group, "at_most_one_of")
+		counter := fmt.Sprintf("atMostOneOf%d", i)
+		p.generateInMask(group.GetFields(), func() {
+			p.P(counter, ` := 0`)
+			for _, condition := range conditions {
+				p.P(`if `, condition, ` {`)
+				p.In()
+				p.P(counter, `++`)
+				p.Out()
+				p.P(`}`)
+			}
+			p.P(`if `, counter, ` > 1 {`)
+			p.In()
+			errorStr := fmt.Sprintf("at most one of the fields %s can be set", strings.Join(group.GetFields(), ", "))
+			p.generateMessageErrorString(p.fieldGroupName(message, group), "at_most_one_of", errorStr)
+			p.Out()
+			p.P(`}`)
+		})
+	}
+}
+
+// messageField looks up a field referenced by name from a rule.
+func (p *plugin) messageField(message *generator.Descriptor, name string, rule string) *descriptor.FieldDescriptorProto {
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	field := message.GetFieldDescriptor(name)
+	if field == nil {
+		p.Fail(fmt.Sprintf("field %v referenced by %v does not exist in %v", name, rule, ccTypeName))
+	}
+	if field.OneofIndex != nil && !p.proto3Optionals[field] {
+		p.Fail(fmt.Sprintf("field %v.%v is part of a oneof and cannot be used in %v, use validator.oneof instead", ccTypeName, name, rule))
+	}
+	return field
+}
+
+func (p *plugin) fieldGroupConditions(file *generator.FileDescriptor, message *generator.Descriptor, group *validator.FieldGroup, rule string) []string {
+	if len(group.GetFields()) < 2 {
+		log.Printf("WARNING: validator.message %v of %v has less than two fields\n", rule, generator.CamelCaseSlice(message.TypeName()))
+	}
+	conditions := make([]string, 0, len(group.GetFields()))
+	for _, name := range group.GetFields() {
+		conditions = append(conditions, p.fieldIsSetExpr(file, message, p.messageField(message, name, "validator.message "+rule)))
+	}
+	if len(conditions) == 0 {
+		conditions = append(conditions, "false")
+	}
+	return conditions
+}
+
+func (p *plugin) fieldGroupName(message *generator.Descriptor, group *validator.FieldGroup) string {
+	if group.GetName() != "" {
+		return group.GetName()
+	}
+	if len(group.GetFields()) == 0 {
+		return ""
+	}
+	return p.GetFieldName(message, message.GetFieldDescriptor(group.GetFields()[0]))
+}
+
+func (p *plugin) generateConditionalValidators(file *generator.FileDescriptor, message *generator.Descriptor) {
+	for _, field := range message.Field {
+		p.generateInMask([]string{field.GetName()}, func() {
+			for _, set := range p.fieldRuleSets(field, true) {
+				p.generateConditionalValidator(file, message, field, set)
+			}
+		})
+	}
+}
+
+func (p *plugin) generateConditionalValidator(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto, set fieldRuleSet) {
+	fv := set.fv
+	if fv.GetRequiredIf() == nil && fv.GetRequiredUnless() == nil && fv.GetForbiddenIf() == nil {
+		return
+	}
+	p.generateInGroups(set.groups)
+	defer p.generateInGroupsEnd()
+	rules := []struct {
+		violation string
+		condition *validator.FieldCondition
+	}{
+		{"required_if", fv.GetRequiredIf()},
+		{"required_unless", fv.GetRequiredUnless()},
+		{"forbidden_if", fv.GetForbiddenIf()},
+	}
+	fieldName := p.GetFieldName(message, field)
+	isSet := p.fieldIsSetExpr(file, message, p.messageField(message, field.GetName(), "validator.field required_if/required_unless/forbidden_if"))
+	for _, rule := range rules {
+		if rule.condition == nil {
+			continue
 		}
+		condition, description := p.fieldConditionExpr(file, message, rule.violation, rule.condition)
+		var check, errorStr string
+		switch rule.violation {
+		case "required_if":
+			check = `(` + condition + `) && !(` + isSet + `)`
+			errorStr = "value must be set when " + description
+		case "required_unless":
+			check = `!(` + condition + `) && !(` + isSet + `)`
+			errorStr = "value must be set unless " + description
+		case "forbidden_if":
+			check = `(` + condition + `) && (` + isSet + `)`
+			errorStr = "value must not be set when " + description
+		}
+		p.P(`if `, check, ` {`)
+		p.In()
+		if isWarning(fv) {
+			p.P(`validations.AddValidationWarning("`, fieldName, `", "`, rule.violation, `", "`, errorStr, `")`)
+		} else {
+			p.generateMessageErrorString(fieldName, rule.violation, errorStr)
+		}
+		p.Out()
+		p.P(`}`)
+	}
+}
+
+// fieldConditionExpr returns a Go expression which is true when a validator.FieldCondition holds, and its
+// description for error messages.
+func (p *plugin) fieldConditionExpr(file *generator.FileDescriptor, message *generator.Descriptor, rule string, condition *validator.FieldCondition) (string, string) {
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	sibling := p.messageField(message, condition.GetField(), "validator.field "+rule)
+	isSet := p.fieldIsSetExpr(file, message, sibling)
+	if len(condition.GetIn()) == 0 {
+		return isSet, condition.GetField() + " is set"
+	}
+	if sibling.IsRepeated() || sibling.IsMessage() || sibling.IsBytes() {
+		p.Fail(fmt.Sprintf("field %v.%v can only be checked for presence in %v, values are not supported", ccTypeName, condition.GetField(), rule))
+	}
+	getter := "this.Get" + p.GetFieldName(message, sibling) + "()"
+	comparisons := make([]string, 0, len(condition.GetIn()))
+	for _, value := range condition.GetIn() {
+		goValue, err := p.fieldValueLiteral(sibling, value)
+		if err != nil {
+			p.Fail(fmt.Sprintf("value %q of %v on %v.%v: %v", value, rule, ccTypeName, condition.GetField(), err))
+		}
+		switch goValue {
+		case "true":
+			comparisons = append(comparisons, getter)
+		case "false":
+			comparisons = append(comparisons, "!"+getter)
+		default:
+			comparisons = append(comparisons, getter+" == "+goValue)
+		}
+	}
+	expr := strings.Join(comparisons, " || ")
+	if !gogoproto.IsProto3(file.FileDescriptorProto) {
+		expr = isSet + " && (" + expr + ")"
 	}
+	return expr, condition.GetField() + " is " + strings.Join(condition.GetIn(), " or ")
 }
 
-func (p *plugin) generateIntValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
+// fieldValueLiteral converts the text of a scalar value of a field to a Go literal.
+func (p *plugin) fieldValueLiteral(field *descriptor.FieldDescriptorProto, value string) (string, error) {
+	switch {
+	case field.IsEnum():
+		if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return value, nil
+		}
+		enum := p.ObjectNamed(field.GetTypeName()).(*generator.EnumDescriptor)
+		for _, v := range enum.Value {
+			if v.GetName() == value {
+				return strconv.Itoa(int(v.GetNumber())), nil
+			}
+		}
+		return "", fmt.Errorf("not a value of %v", strings.Join(enum.TypeName(), "."))
+	case field.IsString():
+		return strconv.Quote(value), nil
+	case field.IsBool():
+		b, err := strconv.ParseBool(value)
+		return strconv.FormatBool(b), err
+	case p.isSupportedInt(field):
+		_, err := strconv.ParseInt(value, 10, 64)
+		return value, err
+	case p.isSupportedFloat(field):
+		_, err := strconv.ParseFloat(value, 64)
+		return value, err
+	}
+	return "", fmt.Errorf("unsupported field type")
+}
+
+// fieldIsSetExpr returns a Go expression which is true when a non-oneof field of the message holds a value.
+func (p *plugin) fieldIsSetExpr(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto) string {
+	return p.fieldOfIsSetExpr("this", file, message, field)
+}
+
+// fieldOfIsSetExpr returns a Go expression which is true when a non-oneof field of the message held by receiver holds
+// a value.
+func (p *plugin) fieldOfIsSetExpr(receiver string, file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto) string {
+	variableName := receiver + "." + p.GetFieldName(message, field)
+	proto3 := gogoproto.IsProto3(file.FileDescriptorProto)
+	switch {
+	case field.IsRepeated():
+		return "len(" + variableName + ") > 0"
+	case p.proto3Optionals[field]:
+		return variableName + " != nil"
+	case field.IsBytes():
+		if proto3 {
+			return "len(" + variableName + ") > 0"
+		}
+		return variableName + " != nil"
+	case field.IsMessage():
+		if !gogoproto.IsNullable(field) || (p.useGogoImport && gogoproto.IsEmbed(field)) {
+			p.Fail(fmt.Sprintf("field %v.%v is not nullable, its presence cannot be checked", generator.CamelCaseSlice(message.TypeName()), field.GetName()))
+		}
+		return variableName + " != nil"
+	case !proto3 && gogoproto.IsNullable(field):
+		return variableName + " != nil"
+	case field.IsString():
+		return variableName + ` != ""`
+	case field.IsBool():
+		return variableName
+	}
+	return variableName + " != 0"
+}
+
+func (p *plugin) generateMessageErrorString(fieldName, violation, errorStr string) {
+	p.P(p.addValidationCall("AddValidationError", fieldName, violation), `"`, errorStr, `")`)
+}
+
+// skipsNestedValidation reports whether the message type of a field opted out of being validated as a field.
+func (p *plugin) skipsNestedValidation(field *descriptor.FieldDescriptorProto) bool {
+	if desc, ok := p.ObjectNamed(field.GetTypeName()).(*generator.Descriptor); ok {
+		return getMessageValidatorIfAny(desc.DescriptorProto).GetSkipNested()
+	}
+	return false
+}
+
+func (p *plugin) generateIntValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator) {
+	if fv == nil {
+		return
+	}
 	if fv.IntGt != nil {
 		p.P(`if !(`, variableName, ` > `, fv.IntGt, `) {`)
 		p.In()
 		errorStr := fmt.Sprintf(`be greater than '%d'`, fv.GetIntGt())
-		p.generateErrorString(variableName, fieldName, "int_gt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "int_gt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -517,7 +1163,7 @@ func (p *plugin) generateIntValidator(variableName string, _ string, fieldName s
 		p.P(`if !(`, variableName, ` < `, fv.IntLt, `) {`)
 		p.In()
 		errorStr := fmt.Sprintf(`be less than '%d'`, fv.GetIntLt())
-		p.generateErrorString(variableName, fieldName, "int_lt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "int_lt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -526,24 +1172,26 @@ func (p *plugin) generateIntValidator(variableName string, _ string, fieldName s
 func (p *plugin) generateEnumValidator(
 	field *descriptor.FieldDescriptorProto,
 	variableName, _, fieldName string,
-	fv *validator.FieldValidator,
-	assignInsteadReturn bool) {
+	fv *validator.FieldValidator) {
 	if fv.GetIsInEnum() {
 		enum := p.ObjectNamed(field.GetTypeName()).(*generator.EnumDescriptor)
 		p.P(`if _, ok := `, strings.Join(enum.TypeName(), "_"), "_name[int32(", variableName, ")]; !ok {")
 		p.In()
-		p.generateErrorString(variableName, fieldName, "is_in_enum", fmt.Sprintf("be a valid %s field", strings.Join(enum.TypeName(), "_")), fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "is_in_enum", fmt.Sprintf("be a valid %s field", strings.Join(enum.TypeName(), "_")), fv)
 		p.Out()
 		p.P(`}`)
 	}
 }
 
-func (p *plugin) generateLengthValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
+func (p *plugin) generateLengthValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator) {
+	if fv == nil {
+		return
+	}
 	if fv.LengthGt != nil {
 		p.P(`if !( len(`, variableName, `) > `, fv.LengthGt, `) {`)
 		p.In()
 		errorStr := fmt.Sprintf(`have a length greater than '%d'`, fv.GetLengthGt())
-		p.generateErrorString(variableName, fieldName, "length_gt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "length_gt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -552,7 +1200,7 @@ func (p *plugin) generateLengthValidator(variableName string, _ string, fieldNam
 		p.P(`if !( len(`, variableName, `) < `, fv.LengthLt, `) {`)
 		p.In()
 		errorStr := fmt.Sprintf(`have a length smaller than '%d'`, fv.GetLengthLt())
-		p.generateErrorString(variableName, fieldName, "length_lt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "length_lt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -561,24 +1209,27 @@ func (p *plugin) generateLengthValidator(variableName string, _ string, fieldNam
 		p.P(`if !( len(`, variableName, `) == `, fv.LengthEq, `) {`)
 		p.In()
 		errorStr := fmt.Sprintf(`have a length equal to '%d'`, fv.GetLengthEq())
-		p.generateErrorString(variableName, fieldName, "length_eq", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "length_eq", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
 }
 
-func (p *plugin) generateRequiredValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
-	if fv.Required != nil && *fv.Required == true {
+func (p *plugin) generateRequiredValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator) {
+	if fv.GetRequired() {
 		p.P(`if nil == `, variableName, ` {`)
 		p.In()
 		errorStr := fmt.Sprintf(`%s is required`, fieldName)
-		p.generateErrorString(variableName, fieldName, "required", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "required", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
 }
 
-func (p *plugin) generateFloatValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
+func (p *plugin) generateFloatValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator) {
+	if fv == nil {
+		return
+	}
 	upperIsStrict := true
 	lowerIsStrict := true
 
@@ -632,7 +1283,7 @@ func (p *plugin) generateFloatValidator(variableName string, ccTypeName string,
 		}
 		p.P(compareStr)
 		p.In()
-		p.generateErrorString(variableName, fieldName, "float_gt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "float_gt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -652,7 +1303,7 @@ func (p *plugin) generateFloatValidator(variableName string, ccTypeName string,
 		}
 		p.P(compareStr)
 		p.In()
-		p.generateErrorString(variableName, fieldName, "float_lt", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "float_lt", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -664,16 +1315,14 @@ func (p *plugin) generateFloatValidator(variableName string, ccTypeName string,
 func getUUIDRegex(version *int32) (string, error) {
 	if version == nil {
 		return "", nil
-	} else if *version < 0 || *version > 5 {
-		return "", fmt.Errorf("UUID version should be between 0-5, Got %d", *version)
-	} else if *version == 0 {
-		return fmt.Sprintf(uuidPattern, "1-5"), nil
-	} else {
-		return fmt.Sprintf(uuidPattern, strconv.Itoa(int(*version))), nil
 	}
+	return validator.UUIDRegex(*version)
 }
 
-func (p *plugin) generateStringValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
+func (p *plugin) generateStringValidator(variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator) {
+	if fv == nil {
+		return
+	}
 	if fv.Regex != nil || fv.UuidVer != nil {
 		if fv.UuidVer != nil {
 			uuid, err := getUUIDRegex(fv.UuidVer)
@@ -687,7 +1336,7 @@ func (p *plugin) generateStringValidator(variableName string, ccTypeName string,
 		p.P(`if !`, p.regexName(ccTypeName, fieldName), `.MatchString(`, variableName, `) {`)
 		p.In()
 		errorStr := "be a string conforming to regex " + strconv.Quote(fv.GetRegex())
-		p.generateErrorString(variableName, fieldName, "regex", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "regex", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -695,14 +1344,14 @@ func (p *plugin) generateStringValidator(variableName string, ccTypeName string,
 		p.P(`if `, variableName, ` == "" {`)
 		p.In()
 		errorStr := "not be an empty string"
-		p.generateErrorString(variableName, fieldName, "string_not_empty", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "string_not_empty", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
-	p.generateLengthValidator(variableName, ccTypeName, fieldName, fv, assignInsteadReturn)
+	p.generateLengthValidator(variableName, ccTypeName, fieldName, fv)
 }
 
-func (p *plugin) generateRepeatedCountValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator, assignInsteadReturn bool) {
+func (p *plugin) generateRepeatedCountValidator(variableName string, _ string, fieldName string, fv *validator.FieldValidator) {
 	if fv == nil {
 		return
 	}
@@ -711,7 +1360,7 @@ func (p *plugin) generateRepeatedCountValidator(variableName string, _ string, f
 		p.P(compareStr)
 		p.In()
 		errorStr := fmt.Sprint(`contain at least `, fv.GetRepeatedCountMin(), ` elements`)
-		p.generateErrorString(variableName, fieldName, "repeated_count_min", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "repeated_count_min", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
@@ -720,41 +1369,222 @@ func (p *plugin) generateRepeatedCountValidator(variableName string, _ string, f
 		p.P(compareStr)
 		p.In()
 		errorStr := fmt.Sprint(`contain at most `, fv.GetRepeatedCountMax(), ` elements`)
-		p.generateErrorString(variableName, fieldName, "repeated_count_max", errorStr, fv, assignInsteadReturn)
+		p.generateErrorString(variableName, fieldName, "repeated_count_max", errorStr, fv)
 		p.Out()
 		p.P(`}`)
 	}
 }
 
-func (p *plugin) generateErrorFromErr(variableName, fieldName, violation string, assignInsteadReturn bool) {
-	if assignInsteadReturn {
-		if violation == "array" {
-			p.P(`validations.AddValidationsError("`, fieldName, `", "`, violation, `", i, err)`)
+func (p *plugin) generateUniqueValidator(field *descriptor.FieldDescriptorProto, variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator) {
+	if fv == nil || (!fv.GetUnique() && fv.UniqueBy == nil) {
+		return
+	}
+	keyField := field
+	keyExpr := "item"
+	skipExpr := ""
+	if fv.UniqueBy != nil {
+		if !field.IsMessage() {
+			log.Printf("WARNING: field %v.%v is not a message, validator.unique_by has no effect\n", ccTypeName, fieldName)
+			return
+		}
+		msg := p.ObjectNamed(field.GetTypeName()).(*generator.Descriptor)
+		keyField = msg.GetFieldDescriptor(fv.GetUniqueBy())
+		if keyField == nil || keyField.IsMessage() || keyField.IsRepeated() {
+			log.Printf("WARNING: field %v.%v validator.unique_by must name a singular scalar field of %v, it has no effect\n", ccTypeName, fieldName, field.GetTypeName())
 			return
 		}
+		keyExpr = "item.Get" + generator.CamelCase(keyField.GetName()) + "()"
+		// Elements without a key are not compared, a missing element or key is not a duplicate.
+		if keyField.OneofIndex != nil && !p.proto3Optionals[keyField] {
+			skipExpr = "item == nil || " + zeroValueCondition(keyExpr, keyField)
+		} else {
+			skipExpr = "item == nil || !(" + p.fieldOfIsSetExpr("item", msg.File(), msg, keyField) + ")"
+		}
+	} else if field.IsMessage() {
+		log.Printf("WARNING: field %v.%v is a message, use validator.unique_by instead of validator.unique\n", ccTypeName, fieldName)
+		return
+	}
+	keyType := scalarGoType(keyField)
+	if keyField.IsBytes() {
+		keyType, keyExpr = "string", "string("+keyExpr+")"
+	} else if keyField.IsEnum() {
+		keyType, keyExpr = "int32", "int32("+keyExpr+")"
+	}
+	seenName := "unique" + fieldName
+	p.P(seenName, ` := make(map[`, keyType, `]int, len(`, variableName, `))`)
+	p.P(`for i, item := range `, variableName, ` {`)
+	p.In()
+	if skipExpr != "" {
+		p.P(`if `, skipExpr, ` {`)
+		p.In()
+		p.P(`continue`)
+		p.Out()
+		p.P(`}`)
+	}
+	p.P(`if first, ok := `, seenName, `[`, keyExpr, `]; ok {`)
+	p.In()
+	errorStr := "be unique, found duplicates at indexes '%d' and '%d'"
+	if fv.UniqueBy != nil {
+		errorStr = fmt.Sprintf("have a unique '%s', found duplicates at indexes '%%d' and '%%d'", fv.GetUniqueBy())
+	}
+	if fv.GetHumanError() != "" {
+		p.P(`validations.AddValidationsError("`, fieldName, `", "unique", i, "`, fv.GetHumanError(), `")`)
+	} else {
+		p.P(`validations.AddValidationsError("`, fieldName, `", "unique", i, `, p.fmtPkg.Use(), ".Sprintf(`value '%v' must ", errorStr, "`", `, `, keyExpr, `, first, i))`)
+	}
+	p.Out()
+	p.P(`} else {`)
+	p.In()
+	p.P(seenName, `[`, keyExpr, `] = i`)
+	p.Out()
+	p.P(`}`)
+	p.Out()
+	p.P(`}`)
+}
 
-		p.P(`validations.AddValidationError("`, fieldName, `", "`, violation, `", err)`)
+func (p *plugin) generateAnyValidator(variableName string, _ string, fieldName string, violation string, fv *validator.FieldValidator) {
+	if fv == nil {
 		return
 	}
+	typeURL := "(" + variableName + ").GetTypeUrl()"
+	if len(fv.AnyIn) > 0 {
+		urls := validator.AnyTypeURLs(fv.AnyIn)
+		p.P(`if !(`, anyTypeURLCondition(typeURL, urls), `) {`)
+		p.In()
+		errorStr := fmt.Sprintf(`have a type URL in '%s'`, strings.Join(urls, ", "))
+		p.generateErrorString(typeURL, fieldName, "any_in", errorStr, fv)
+		p.Out()
+		p.P(`}`)
+	}
+	if len(fv.AnyNotIn) > 0 {
+		urls := validator.AnyTypeURLs(fv.AnyNotIn)
+		p.P(`if `, anyTypeURLCondition(typeURL, urls), ` {`)
+		p.In()
+		errorStr := fmt.Sprintf(`not have a type URL in '%s'`, strings.Join(urls, ", "))
+		p.generateErrorString(typeURL, fieldName, "any_not_in", errorStr, fv)
+		p.Out()
+		p.P(`}`)
+	}
+	if fv.GetAnyValidate() {
+		p.P(`if err := `, p.validatorPkg.Use(), `.CallContextAnyValidatorsIfExists(ctx, `, variableName, `); err != nil {`)
+		p.In()
+		p.generateErrorFromErr(variableName, fieldName, violation)
+		p.Out()
+		p.P(`}`)
+	}
+}
+
+func (p *plugin) generateFieldMaskValidator(message *generator.Descriptor, variableName string, ccTypeName string, fieldName string, fv *validator.FieldValidator) {
+	targetName := fv.GetFieldMaskTarget()
+	if !strings.HasPrefix(targetName, ".") {
+		targetName = "." + targetName
+	}
+	target, ok := p.ObjectNamed(targetName).(*generator.Descriptor)
+	if !ok {
+		p.Fail(fmt.Sprintf("field %v.%v validator.field_mask_target %v is not a message", ccTypeName, fieldName, fv.GetFieldMaskTarget()))
+	}
+	p.P(`if err := `, p.validatorPkg.Use(), `.CheckFieldMask(`, variableName, `, &`, p.messageTypeName(message, target), `{}); err != nil {`)
+	p.In()
+	p.generateErrorFromErr(variableName, fieldName, "field_mask_target")
+	p.Out()
+	p.P(`}`)
+}
 
-	p.P(`return `, p.validatorPkg.Use(), `.FieldError("`, fieldName, `",`, p.fmtPkg.Use(), ".Errorf(`value '%v' must '%s'`", `, `, variableName, `, err.Error()`, `)`, `)`)
+// messageTypeName returns the Go type of target as written in the package of message.
+func (p *plugin) messageTypeName(message *generator.Descriptor, target *generator.Descriptor) string {
+	typeName := generator.CamelCaseSlice(target.TypeName())
+	if target.GoImportPath() == message.GoImportPath() {
+		return typeName
+	}
+	pkg, ok := p.typePkgs[target.GoImportPath()]
+	if !ok {
+		pkg = p.NewImport(string(target.GoImportPath()))
+		p.typePkgs[target.GoImportPath()] = pkg
+	}
+	return pkg.Use() + "." + typeName
 }
 
-func (p *plugin) generateErrorString(variableName, fieldName, violation, specificError string, fv *validator.FieldValidator, assignInsteadReturn bool) {
-	if assignInsteadReturn {
-		if fv.GetHumanError() != "" {
-			p.P(`validations.AddValidationError("`, fieldName, `", "`, violation, `", "`, fv.GetHumanError(), `")`)
-			return
-		}
-		p.P(`validations.AddValidationError("`, fieldName, `", "`, violation, `", `, p.fmtPkg.Use(), ".Sprintf(`value '%v' must ", specificError, "`", `, `, variableName, `)`, `)`)
-		return
+// scalarGoType returns the Go type of a singular scalar field value.
+func scalarGoType(field *descriptor.FieldDescriptorProto) string {
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "float64"
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "float32"
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_SINT64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "int64"
+	case descriptor.FieldDescriptorProto_TYPE_UINT64, descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "uint64"
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_SINT32, descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "int32"
+	case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "uint32"
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "bool"
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "string"
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "[]byte"
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return "int32"
+	}
+	return "interface{}"
+}
+
+// zeroValueCondition returns a Go expression which is true when the value of a singular scalar field is its zero value.
+func zeroValueCondition(variableName string, field *descriptor.FieldDescriptorProto) string {
+	switch {
+	case field.IsString():
+		return variableName + ` == ""`
+	case field.IsBool():
+		return "!" + variableName
+	case field.IsBytes():
+		return "len(" + variableName + ") == 0"
+	}
+	return variableName + " == 0"
+}
+
+func anyTypeURLCondition(typeURL string, urls []string) string {
+	conditions := make([]string, 0, len(urls))
+	for _, url := range urls {
+		conditions = append(conditions, typeURL+` == `+strconv.Quote(url))
+	}
+	return strings.Join(conditions, ` || `)
+}
+
+func (p *plugin) generateErrorFromErr(variableName, fieldName, violation string) {
+	p.P(p.addValidationCall("AddValidationError", fieldName, violation), `err)`)
+}
+
+// addValidationFunc returns the ValidationErrors method adding a violation of the rules of fv.
+func addValidationFunc(fv *validator.FieldValidator) string {
+	if isWarning(fv) {
+		return "AddValidationWarning"
 	}
+	return "AddValidationError"
+}
+
+// addValidationCall returns the beginning of a call to the ValidationErrors method add for a violation of a field, up
+// to the error. Within the loop on the elements of a repeated field, the violation is added with the index of the
+// element.
+func (p *plugin) addValidationCall(add, fieldName, violation string) string {
+	if p.inRepeatedLoop {
+		return `validations.` + strings.Replace(add, "Validation", "Validations", 1) + `("` + fieldName + `", "` + violation + `", i, `
+	}
+	return `validations.` + add + `("` + fieldName + `", "` + violation + `", `
+}
+
+func isWarning(fv *validator.FieldValidator) bool {
+	return fv.GetSeverity() == validator.Severity_SEVERITY_WARNING
+}
 
+func (p *plugin) generateErrorString(variableName, fieldName, violation, specificError string, fv *validator.FieldValidator) {
+	add := addValidationFunc(fv)
 	if fv.GetHumanError() != "" {
-		p.P(`return `, p.validatorPkg.Use(), `.FieldError("`, fieldName, `",`, p.fmtPkg.Use(), ".Errorf(`", fv.GetHumanError(), "`))")
+		p.P(p.addValidationCall(add, fieldName, violation), `"`, fv.GetHumanError(), `")`)
 		return
 	}
-	p.P(`return `, p.validatorPkg.Use(), `.FieldError("`, fieldName, `",`, p.fmtPkg.Use(), ".Errorf(`value '%v' must ", specificError, "`", `, `, variableName, `))`)
+	p.P(p.addValidationCall(add, fieldName, violation), p.fmtPkg.Use(), ".Sprintf(`value '%v' must ", specificError, "`", `, `, variableName, `)`, `)`)
 }
 
 func (p *plugin) fieldIsProto3Map(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto) bool {
@@ -795,6 +1625,18 @@ func (p *plugin) fieldIsProto3Map(file *generator.FileDescriptor, message *gener
 	return msg.GetOptions().GetMapEntry()
 }
 
+func (p *plugin) isFieldMask(field *descriptor.FieldDescriptorProto) bool {
+	return field.IsMessage() && field.GetTypeName() == fieldMaskTypeName
+}
+
+func (p *plugin) isAny(field *descriptor.FieldDescriptorProto) bool {
+	return field.IsMessage() && field.GetTypeName() == anyTypeName
+}
+
+func (p *plugin) validatorWithAnyConstraint(fv *validator.FieldValidator) bool {
+	return fv != nil && (len(fv.AnyIn) > 0 || len(fv.AnyNotIn) > 0 || fv.AnyValidate != nil)
+}
+
 func (p *plugin) validatorWithMessageExists(fv *validator.FieldValidator) bool {
 	return fv != nil && fv.MsgExists != nil && *(fv.MsgExists)
 }
@@ -805,7 +1647,7 @@ func (p *plugin) validatorWithNonRepeatedConstraint(fv *validator.FieldValidator
 	}
 
 	// Need to use reflection in order to be future-proof for new types of constraints.
-	v := reflect.ValueOf(*fv)
+	v := reflect.ValueOf(fv).Elem()
 	for i := 0; i < v.NumField(); i++ {
 		fieldName := v.Type().Field(i).Name
 
@@ -817,13 +1659,40 @@ func (p *plugin) validatorWithNonRepeatedConstraint(fv *validator.FieldValidator
 		}
 
 		// Identify non-repeated constraints based on their name.
-		if fieldName != "RepeatedCountMin" && fieldName != "RepeatedCountMax" {
+		if fieldName != "RepeatedCountMin" && fieldName != "RepeatedCountMax" && fieldName != "Unique" && fieldName != "UniqueBy" && fieldName != "Items" &&
+			fieldName != "RequiredIf" && fieldName != "RequiredUnless" && fieldName != "ForbiddenIf" &&
+			fieldName != "Immutable" && fieldName != "Monotonic" {
 			return true
 		}
 	}
 	return false
 }
 
+// isImplicitItemValidator reports whether the rules of a repeated field are applied to each of its items, see
+// validator.ImplicitItemRules.
+func (p *plugin) isImplicitItemValidator(field *descriptor.FieldDescriptorProto, fv *validator.FieldValidator) bool {
+	return !p.explicitItemRules && validator.ImplicitItemRules(protoreflect.Kind(field.GetType()), fv)
+}
+
+// itemValidator returns the rules applied to each element of a repeated field. Element rules are declared in
+// validator.items and only collection rules are allowed on the field itself, see validator.ItemRules. Unless the
+// plugin runs with explicit_item_rules, the rules of a field setting element rules without validator.items are
+// applied to each element with a deprecation warning.
+func (p *plugin) itemValidator(field *descriptor.FieldDescriptorProto, ccTypeName string, fieldName string, fv *validator.FieldValidator) *validator.FieldValidator {
+	if p.isImplicitItemValidator(field, fv) {
+		log.Printf("WARNING: field %v.%v sets element rules without validator.items, applying them to each element is deprecated: move them to validator.items\n", ccTypeName, fieldName)
+		return fv
+	}
+	items, err := validator.ItemRules(protoreflect.Kind(field.GetType()), fv)
+	if err != nil {
+		p.Fail(fmt.Sprintf("field %v.%v: %v", ccTypeName, fieldName, err))
+	}
+	return items
+}
+
 func (p *plugin) regexName(ccTypeName string, fieldName string) string {
+	if p.ruleSetIndex > 0 {
+		return fmt.Sprintf("_regex_%s_%s_%d", ccTypeName, fieldName, p.ruleSetIndex)
+	}
 	return "_regex_" + ccTypeName + "_" + fieldName
 }
@@ -0,0 +1,123 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"fmt"
+	"log"
+
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// requestFiles returns the descriptors of the files to generate of a generator request, checking the rules of their
+// repeated fields as the Go generator does.
+func requestFiles(req *plugin_go.CodeGeneratorRequest, opts Options) ([]protoreflect.FileDescriptor, error) {
+	files, err := loadFiles(req.ProtoFile)
+	if err != nil {
+		return nil, err
+	}
+	var generated []protoreflect.FileDescriptor
+	for _, name := range req.FileToGenerate {
+		fd, err := files.FindFileByPath(name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the descriptor of %v: %v", name, err)
+		}
+		if err := checkItemRules(fd, opts.ExplicitItemRules); err != nil {
+			return nil, err
+		}
+		generated = append(generated, fd)
+	}
+	return generated, nil
+}
+
+// checkItemRules checks that the element rules of the repeated fields of a file are set in validator.items, see
+// validator.ItemRules, warning about the deprecated fields setting them on the field itself unless explicitItemRules.
+func checkItemRules(fd protoreflect.FileDescriptor, explicitItemRules bool) error {
+	for _, md := range fileMessages(fd) {
+		rules := descriptorRules(md)
+		for i := 0; i < md.Fields().Len(); i++ {
+			field := md.Fields().Get(i)
+			fv := rules.Fields[string(field.Name())]
+			if !field.IsList() || fv == nil {
+				continue
+			}
+			for _, set := range append([]*validator.FieldValidator{fv}, fv.GetGroupRules()...) {
+				if !explicitItemRules && validator.ImplicitItemRules(field.Kind(), set) {
+					log.Printf("WARNING: field %v sets element rules without validator.items, applying them to each element is deprecated: move them to validator.items\n", field.FullName())
+					continue
+				}
+				if _, err := validator.ItemRules(field.Kind(), set); err != nil {
+					return fmt.Errorf("field %v: %v", field.FullName(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fileMessages lists the messages of a file in declaration order, nested messages included and map entries excluded.
+func fileMessages(fd protoreflect.FileDescriptor) []protoreflect.MessageDescriptor {
+	var messages []protoreflect.MessageDescriptor
+	var add func(protoreflect.MessageDescriptors)
+	add = func(mds protoreflect.MessageDescriptors) {
+		for i := 0; i < mds.Len(); i++ {
+			if mds.Get(i).IsMapEntry() {
+				continue
+			}
+			messages = append(messages, mds.Get(i))
+			add(mds.Get(i).Messages())
+		}
+	}
+	add(fd.Messages())
+	return messages
+}
+
+// descriptorRules returns the rules of a message type, without any rules if its validation is disabled.
+func descriptorRules(md protoreflect.MessageDescriptor) *validator.Rules {
+	rules := validator.DescriptorRules(md)
+	if rules.Message.GetDisabled() {
+		return &validator.Rules{FullName: rules.FullName}
+	}
+	return rules
+}
+
+// defaultRuleSets lists the validators of a field validated in the default group, from the validator set on the field
+// and its group_rules. Warnings are left out as they don't fail the validation.
+func defaultRuleSets(fv *validator.FieldValidator) []*validator.FieldValidator {
+	if fv == nil {
+		return nil
+	}
+	var sets []*validator.FieldValidator
+	for _, set := range append([]*validator.FieldValidator{fv}, fv.GetGroupRules()...) {
+		if !validator.InGroups(nil, set.GetGroups()...) || isWarning(set) {
+			continue
+		}
+		rules := protov2.Clone(set).(*validator.FieldValidator)
+		rules.Groups, rules.GroupRules = nil, nil
+		if rules.Items != nil && rules.Items.Severity == nil {
+			rules.Items.Severity = rules.Severity
+		}
+		if isWarning(rules.Items) {
+			rules.Items = nil
+		}
+		sets = append(sets, rules)
+	}
+	return sets
+}
+
+// repeatedRules splits the rules of a repeated field into the rules of the field as a whole and the rules applied to
+// each of its elements, see plugin.itemValidator.
+func repeatedRules(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator, explicitItemRules bool) (collection, items *validator.FieldValidator) {
+	if explicitItemRules || !validator.ImplicitItemRules(fd.Kind(), fv) {
+		return fv, fv.GetItems()
+	}
+	// length_* bound each element
+	collection = protov2.Clone(fv).(*validator.FieldValidator)
+	collection.LengthGt, collection.LengthLt, collection.LengthEq = nil, nil, nil
+	return collection, fv
+}
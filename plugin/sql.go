@@ -0,0 +1,268 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gogo/protobuf/proto"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// sqlGenerator writes the constraints of the tables persisting the messages of a proto file.
+type sqlGenerator struct {
+	explicitItemRules bool
+	naming            string
+	enumNames         bool
+	buf               bytes.Buffer
+}
+
+// GenerateSQL generates the PostgreSQL DDL adding CHECK and NOT NULL constraints to the tables persisting the messages
+// of each file to generate, "<dir>/<file>.validators.sql". Each message with rules is expected in a table named after
+// it in snake case, with a column per field named after opts.SQLNaming: "snake" (the default) for the snake case of
+// the field name, "proto" for the field name and "json" for its protojson name. Enums are stored as numbers, or as
+// value names with opts.SQLEnums "name".
+func GenerateSQL(req *plugin_go.CodeGeneratorRequest, opts Options) (*plugin_go.CodeGeneratorResponse, error) {
+	g := &sqlGenerator{explicitItemRules: opts.ExplicitItemRules, naming: opts.SQLNaming}
+	if g.naming == "" {
+		g.naming = "snake"
+	}
+	if g.naming != "snake" && g.naming != "proto" && g.naming != "json" {
+		return nil, fmt.Errorf("unknown SQL naming '%s'", g.naming)
+	}
+	switch opts.SQLEnums {
+	case "", "number":
+	case "name":
+		g.enumNames = true
+	default:
+		return nil, fmt.Errorf("unknown SQL enum format '%s'", opts.SQLEnums)
+	}
+	files, err := requestFiles(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	for _, fd := range files {
+		g.buf.Reset()
+		g.p("-- Code generated by protoc-gen-govalidators. DO NOT EDIT.")
+		g.p("-- source: ", fd.Path())
+		for _, md := range fileMessages(fd) {
+			g.generateMessage(md)
+		}
+		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(fd.Path(), ".proto") + ".validators.sql"),
+			Content: proto.String(g.buf.String()),
+		})
+	}
+	return resp, nil
+}
+
+func (g *sqlGenerator) p(str ...string) {
+	g.buf.WriteString(strings.Join(str, "") + "\n")
+}
+
+func (g *sqlGenerator) generateMessage(md protoreflect.MessageDescriptor) {
+	rules := descriptorRules(md)
+	table := snakeCase(strings.TrimPrefix(string(md.FullName()), string(md.ParentFile().Package())+"."))
+	var statements []string
+	notNull := func(fd protoreflect.FieldDescriptor) {
+		statement := "ALTER TABLE " + sqlIdentifier(table) + " ALTER COLUMN " + sqlIdentifier(g.column(fd)) + " SET NOT NULL;"
+		if !containsString(statements, statement) {
+			statements = append(statements, statement)
+		}
+	}
+	check := func(name string, conditions []string) {
+		if len(conditions) > 0 {
+			statements = append(statements, "ALTER TABLE "+sqlIdentifier(table)+" ADD CONSTRAINT "+sqlIdentifier(name)+
+				" CHECK ("+strings.Join(conditions, " AND ")+");")
+		}
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if fd.Cardinality() == protoreflect.Required || containsString(rules.Message.GetRequired(), string(fd.Name())) {
+			notNull(fd)
+		}
+		var conditions []string
+		for _, fv := range defaultRuleSets(rules.Fields[string(fd.Name())]) {
+			if fv.GetRequired() || (fv.GetMsgExists() && !fd.IsList() && !fd.IsMap()) {
+				notNull(fd)
+			}
+			conditions = append(conditions, g.fieldConditions(fd, fv)...)
+		}
+		check(table+"_"+g.column(fd)+"_check", conditions)
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		var columns []string
+		for j := 0; j < od.Fields().Len(); j++ {
+			columns = append(columns, sqlIdentifier(g.column(od.Fields().Get(j))))
+		}
+		operator := " <= 1"
+		if rules.Oneofs[string(od.Name())].GetRequired() {
+			operator = " = 1"
+		}
+		check(table+"_"+snakeCase(string(od.Name()))+"_check", []string{"num_nonnulls(" + strings.Join(columns, ", ") + ")" + operator})
+	}
+	for _, groups := range []struct {
+		groups   []*validator.FieldGroup
+		operator string
+	}{
+		{rules.Message.GetAtLeastOneOf(), " >= 1"},
+		{rules.Message.GetAtMostOneOf(), " <= 1"},
+	} {
+		for _, group := range groups.groups {
+			var columns []string
+			for _, name := range group.GetFields() {
+				if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+					columns = append(columns, sqlIdentifier(g.column(fd)))
+				}
+			}
+			name := group.GetName()
+			if name == "" && len(group.GetFields()) > 0 {
+				name = group.GetFields()[0]
+			}
+			check(table+"_"+snakeCase(name)+"_check", []string{"num_nonnulls(" + strings.Join(columns, ", ") + ")" + groups.operator})
+		}
+	}
+	if len(rules.Message.GetCel()) > 0 {
+		log.Printf("WARNING: message %v has cel rules, they are not part of the SQL constraints\n", md.FullName())
+	}
+	if len(statements) == 0 {
+		return
+	}
+	g.p()
+	g.p("-- ", string(md.FullName()))
+	for _, statement := range statements {
+		g.p(statement)
+	}
+}
+
+// column returns the name of the column of a field.
+func (g *sqlGenerator) column(fd protoreflect.FieldDescriptor) string {
+	switch g.naming {
+	case "proto":
+		return string(fd.Name())
+	case "json":
+		return fd.JSONName()
+	}
+	return snakeCase(string(fd.Name()))
+}
+
+// fieldConditions returns the SQL conditions checking the rules of fv on the column of a singular scalar field.
+func (g *sqlGenerator) fieldConditions(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) []string {
+	if len(fv.GetCel()) > 0 || fv.RequiredIf != nil || fv.RequiredUnless != nil || fv.ForbiddenIf != nil {
+		log.Printf("WARNING: field %v has cel or conditional rules, they are not part of the SQL constraints\n", fd.FullName())
+	}
+	column := sqlIdentifier(g.column(fd))
+	var conditions []string
+	if fd.IsList() {
+		// repeated fields are stored in arrays
+		if fv.RepeatedCountMin != nil {
+			conditions = append(conditions, "cardinality("+column+") >= "+strconv.FormatInt(fv.GetRepeatedCountMin(), 10))
+		}
+		if fv.RepeatedCountMax != nil {
+			conditions = append(conditions, "cardinality("+column+") <= "+strconv.FormatInt(fv.GetRepeatedCountMax(), 10))
+		}
+		if _, items := repeatedRules(fd, fv, g.explicitItemRules); items != nil && fd.Message() == nil {
+			log.Printf("WARNING: field %v has element rules, they are not part of the SQL constraints\n", fd.FullName())
+		}
+		return conditions
+	}
+	if fd.IsMap() || fd.Message() != nil {
+		return nil
+	}
+	if fv.IntGt != nil {
+		conditions = append(conditions, column+" > "+strconv.FormatInt(fv.GetIntGt(), 10))
+	}
+	if fv.IntLt != nil {
+		conditions = append(conditions, column+" < "+strconv.FormatInt(fv.GetIntLt(), 10))
+	}
+	if fv.FloatGt != nil {
+		conditions = append(conditions, column+" > "+string(floatNumber(fv.GetFloatGt()-fv.GetFloatEpsilon())))
+	}
+	if fv.FloatGte != nil {
+		conditions = append(conditions, column+" >= "+string(floatNumber(fv.GetFloatGte())))
+	}
+	if fv.FloatLt != nil {
+		conditions = append(conditions, column+" < "+string(floatNumber(fv.GetFloatLt()+fv.GetFloatEpsilon())))
+	}
+	if fv.FloatLte != nil {
+		conditions = append(conditions, column+" <= "+string(floatNumber(fv.GetFloatLte())))
+	}
+	if fv.GetStringNotEmpty() {
+		conditions = append(conditions, column+" <> ''")
+	}
+	// the generated code compares the number of bytes of strings
+	if fv.LengthGt != nil {
+		conditions = append(conditions, "octet_length("+column+") > "+strconv.FormatInt(fv.GetLengthGt(), 10))
+	}
+	if fv.LengthLt != nil {
+		conditions = append(conditions, "octet_length("+column+") < "+strconv.FormatInt(fv.GetLengthLt(), 10))
+	}
+	if fv.LengthEq != nil {
+		conditions = append(conditions, "octet_length("+column+") = "+strconv.FormatInt(fv.GetLengthEq(), 10))
+	}
+	regex := fv.Regex
+	if fv.UuidVer != nil {
+		if uuid, err := getUUIDRegex(fv.UuidVer); err == nil {
+			regex = &uuid
+		}
+	}
+	if regex != nil {
+		if strings.Contains(*regex, `\p`) || strings.Contains(*regex, `\P`) || strings.Contains(*regex, `\z`) ||
+			strings.Contains(*regex, `\Q`) || strings.Contains(*regex, "(?P<") {
+			log.Printf("WARNING: field %v has a regex with RE2 syntax PostgreSQL doesn't support: %s\n", fd.FullName(), *regex)
+		}
+		conditions = append(conditions, column+" ~ "+sqlString(*regex))
+	}
+	if fv.GetIsInEnum() && fd.Enum() != nil {
+		var values []string
+		for i := 0; i < fd.Enum().Values().Len(); i++ {
+			value := fd.Enum().Values().Get(i)
+			if g.enumNames {
+				values = append(values, sqlString(string(value.Name())))
+			} else if !containsString(values, strconv.Itoa(int(value.Number()))) {
+				values = append(values, strconv.Itoa(int(value.Number())))
+			}
+		}
+		conditions = append(conditions, column+" IN ("+strings.Join(values, ", ")+")")
+	}
+	return conditions
+}
+
+// snakeCase converts a name such as SomeInt or UUID4NotEmpty to some_int and uuid4_not_empty, nested names being
+// joined with underscores.
+func snakeCase(name string) string {
+	runes := []rune(strings.Replace(name, ".", "_", -1))
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' {
+			previous := runes[i-1]
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(previous)) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func sqlIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func sqlString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,189 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gogo/protobuf/gogoproto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// generateTransitionFunc generates the ValidateTransition method, checking the immutable, monotonic and transitions
+// rules of the fields of a message against its previous version, along with those of its nested messages. Like
+// ValidateAll, it checks the rules of the default group and reports the rules with a warning severity as warnings. The
+// method is only generated for messages which have such rules.
+func (p *plugin) generateTransitionFunc(file *generator.FileDescriptor, message *generator.Descriptor) {
+	if !p.hasTransitionRules(message, map[*generator.Descriptor]bool{}) {
+		return
+	}
+	ccTypeName := generator.CamelCaseSlice(message.TypeName())
+	p.P(`func (this *`, ccTypeName, `) ValidateTransition(old *`, ccTypeName, `) error {`)
+	p.In()
+	p.P(`if old == nil {`)
+	p.In()
+	p.P(`return nil`)
+	p.Out()
+	p.P(`}`)
+	p.P(`validations := &`, p.validatorPkg.Use(), `.ValidationErrors{}`)
+	for _, field := range message.Field {
+		fieldName := p.GetOneOfFieldName(message, field)
+		getter := `.Get` + fieldName + `()`
+		for _, rules := range p.fieldRuleSets(field, false) {
+			fieldValidator := rules.fv
+			if fieldValidator.GetImmutable() {
+				p.generateImmutableValidator(field, fieldName, "this"+getter, "old"+getter, fieldValidator)
+			}
+			if fieldValidator.GetMonotonic() != validator.Monotonic_MONOTONIC_UNSPECIFIED {
+				p.generateMonotonicValidator(field, ccTypeName, fieldName, "this"+getter, "old"+getter, fieldValidator)
+			}
+			if len(fieldValidator.GetTransitions()) > 0 {
+				p.generateEnumTransitionValidator(field, ccTypeName, fieldName, "this"+getter, "old"+getter, fieldValidator)
+			}
+		}
+		if nested := p.transitionMessage(field); nested != nil && p.hasTransitionRules(nested, map[*generator.Descriptor]bool{}) {
+			p.generateNestedTransitionValidator(file, message, field, fieldName)
+		}
+	}
+	p.P(`return validations.Err()`)
+	p.Out()
+	p.P(`}`)
+}
+
+// hasTransitionRules reports whether a message or one of its nested messages has rules checked by ValidateTransition.
+func (p *plugin) hasTransitionRules(message *generator.Descriptor, seen map[*generator.Descriptor]bool) bool {
+	if seen[message] || getMessageValidatorIfAny(message.DescriptorProto).GetDisabled() {
+		return false
+	}
+	seen[message] = true
+	for _, field := range message.Field {
+		fv := getFieldValidatorIfAny(field)
+		for _, rules := range append([]*validator.FieldValidator{fv}, fv.GetGroupRules()...) {
+			if rules != nil && validator.InGroups(nil, rules.GetGroups()...) &&
+				(rules.GetImmutable() || rules.GetMonotonic() != validator.Monotonic_MONOTONIC_UNSPECIFIED || len(rules.GetTransitions()) > 0) {
+				return true
+			}
+		}
+		if nested := p.transitionMessage(field); nested != nil && p.hasTransitionRules(nested, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionMessage returns the message type of a singular message field whose previous version is checked along with
+// its message, nil for other fields.
+func (p *plugin) transitionMessage(field *descriptor.FieldDescriptorProto) *generator.Descriptor {
+	if !field.IsMessage() || field.IsRepeated() || p.skipsNestedValidation(field) {
+		return nil
+	}
+	nested, _ := p.ObjectNamed(field.GetTypeName()).(*generator.Descriptor)
+	return nested
+}
+
+func (p *plugin) generateNestedTransitionValidator(file *generator.FileDescriptor, message *generator.Descriptor, field *descriptor.FieldDescriptorProto, fieldName string) {
+	value, oldValue := "this.Get"+fieldName+"()", "old.Get"+fieldName+"()"
+	nonNullable := gogoproto.ImportsGoGoProto(file.FileDescriptorProto) && (!gogoproto.IsNullable(field) || (p.useGogoImport && gogoproto.IsEmbed(field)))
+	if nonNullable {
+		value, oldValue = "&this."+p.GetFieldName(message, field), "&old."+p.GetFieldName(message, field)
+	} else {
+		p.P(`if `, value, ` != nil && `, oldValue, ` != nil {`)
+		p.In()
+	}
+	p.P(`if err := `, value, `.ValidateTransition(`, oldValue, `); err != nil {`)
+	p.In()
+	p.P(`validations.AddValidationError("`, fieldName, `", "message", err)`)
+	p.Out()
+	p.P(`}`)
+	if !nonNullable {
+		p.Out()
+		p.P(`}`)
+	}
+}
+
+func (p *plugin) generateImmutableValidator(field *descriptor.FieldDescriptorProto, fieldName string, value string, oldValue string, fv *validator.FieldValidator) {
+	if field.IsRepeated() || field.IsMessage() || field.IsBytes() {
+		p.P(`if !`, p.validatorPkg.Use(), `.EqualValues(`, value, `, `, oldValue, `) {`)
+	} else {
+		p.P(`if `, value, ` != `, oldValue, ` {`)
+	}
+	p.In()
+	p.P(`validations.`, addValidationFunc(fv), `("`, fieldName, `", "immutable", "value must not change")`)
+	p.Out()
+	p.P(`}`)
+}
+
+func (p *plugin) generateMonotonicValidator(field *descriptor.FieldDescriptorProto, ccTypeName string, fieldName string, value string, oldValue string, fv *validator.FieldValidator) {
+	if field.IsRepeated() || !(p.isSupportedInt(field) || p.isSupportedFloat(field)) {
+		log.Printf("WARNING: field %v.%v is not a singular numeric field, validator.monotonic has no effect\n", ccTypeName, fieldName)
+		return
+	}
+	var operator, errorStr string
+	switch monotonic := fv.GetMonotonic(); monotonic {
+	case validator.Monotonic_MONOTONIC_INCREASING:
+		operator, errorStr = ">=", "be greater than or equal to"
+	case validator.Monotonic_MONOTONIC_STRICTLY_INCREASING:
+		operator, errorStr = ">", "be greater than"
+	case validator.Monotonic_MONOTONIC_DECREASING:
+		operator, errorStr = "<=", "be less than or equal to"
+	case validator.Monotonic_MONOTONIC_STRICTLY_DECREASING:
+		operator, errorStr = "<", "be less than"
+	default:
+		log.Printf("WARNING: field %v.%v has an unknown validator.monotonic %v\n", ccTypeName, fieldName, monotonic)
+		return
+	}
+	p.P(`if !(`, value, ` `, operator, ` `, oldValue, `) {`)
+	p.In()
+	p.P(`validations.`, addValidationFunc(fv), `("`, fieldName, `", "monotonic", `, p.fmtPkg.Use(), ".Sprintf(`value '%v' must ", errorStr, " the previous value '%v'`", `, `, value, `, `, oldValue, `))`)
+	p.Out()
+	p.P(`}`)
+}
+
+func (p *plugin) generateEnumTransitionValidator(field *descriptor.FieldDescriptorProto, ccTypeName string, fieldName string, value string, oldValue string, fv *validator.FieldValidator) {
+	if field.IsRepeated() || !field.IsEnum() {
+		log.Printf("WARNING: field %v.%v is not a singular enum field, validator.transitions have no effect\n", ccTypeName, fieldName)
+		return
+	}
+	allowed := map[string][]string{}
+	var from []string
+	for _, transition := range fv.GetTransitions() {
+		fromValue := p.enumTransitionValue(field, ccTypeName, fieldName, transition.GetFrom())
+		if _, ok := allowed[fromValue]; !ok {
+			from = append(from, fromValue)
+			allowed[fromValue] = nil
+		}
+		for _, to := range transition.GetTo() {
+			allowed[fromValue] = append(allowed[fromValue], value+" == "+p.enumTransitionValue(field, ccTypeName, fieldName, to))
+		}
+	}
+	conditions := make([]string, 0, len(from))
+	for _, fromValue := range from {
+		if len(allowed[fromValue]) == 0 {
+			continue
+		}
+		conditions = append(conditions, oldValue+" == "+fromValue+" && ("+strings.Join(allowed[fromValue], " || ")+")")
+	}
+	condition := value + " != " + oldValue
+	if len(conditions) > 0 {
+		condition += " && !(" + strings.Join(conditions, " || ") + ")"
+	}
+	p.P(`if `, condition, ` {`)
+	p.In()
+	p.P(`validations.`, addValidationFunc(fv), `("`, fieldName, `", "transitions", `, p.fmtPkg.Use(), ".Sprintf(`value '%v' must be a valid transition from '%v'`", `, `, value, `, `, oldValue, `))`)
+	p.Out()
+	p.P(`}`)
+}
+
+func (p *plugin) enumTransitionValue(field *descriptor.FieldDescriptorProto, ccTypeName string, fieldName string, value string) string {
+	goValue, err := p.fieldValueLiteral(field, value)
+	if err != nil {
+		p.Fail(fmt.Sprintf("value %q of validator.transitions on %v.%v: %v", value, ccTypeName, fieldName, err))
+	}
+	return goValue
+}
@@ -0,0 +1,855 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gogo/protobuf/proto"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+const zodHelpers = `const byteLength = (s: string): number => new TextEncoder().encode(s).length;
+const base64Length = (s: string): number => Math.floor((s.replace(/=+$/, "").length * 3) / 4);
+`
+
+// zodGenerator writes the Zod schemas of the messages of a proto file.
+type zodGenerator struct {
+	explicitItemRules bool
+	file              protoreflect.FileDescriptor
+	// names are the identifiers of the schemas of the messages used in the file.
+	names map[protoreflect.FullName]string
+	used  map[string]bool
+	// imports are the schemas imported from the files generated for other proto files, by module path.
+	imports map[string][]string
+	helpers bool
+	body    strings.Builder
+}
+
+// GenerateZod generates a TypeScript file of Zod (v3) schemas for each file to generate, "<dir>/<file>.zod.ts",
+// checking the protojson form of its messages with the rules of the default group. Rules which cannot be expressed
+// with Zod fail the generation.
+func GenerateZod(req *plugin_go.CodeGeneratorRequest, opts Options) (*plugin_go.CodeGeneratorResponse, error) {
+	files, err := requestFiles(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	for _, fd := range files {
+		g := &zodGenerator{
+			explicitItemRules: opts.ExplicitItemRules,
+			file:              fd,
+			names:             map[protoreflect.FullName]string{},
+			used:              map[string]bool{},
+			imports:           map[string][]string{},
+		}
+		content, err := g.generate()
+		if err != nil {
+			return nil, err
+		}
+		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(fd.Path(), ".proto") + ".zod.ts"),
+			Content: proto.String(content),
+		})
+	}
+	return resp, nil
+}
+
+func (g *zodGenerator) generate() (string, error) {
+	messages := fileMessages(g.file)
+	for _, md := range messages {
+		g.schemaName(md)
+	}
+	cyclic := cyclicMessages(messages)
+	for _, md := range messages {
+		if err := g.generateMessage(md, cyclic[md.FullName()]); err != nil {
+			return "", err
+		}
+	}
+	var content strings.Builder
+	content.WriteString("// Code generated by protoc-gen-govalidators. DO NOT EDIT.\n")
+	content.WriteString("// source: " + g.file.Path() + "\n\n")
+	content.WriteString("import { z } from \"zod\";\n")
+	modules := make([]string, 0, len(g.imports))
+	for module := range g.imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		content.WriteString("import { " + strings.Join(g.imports[module], ", ") + " } from \"" + module + "\";\n")
+	}
+	content.WriteString("\n")
+	if g.helpers {
+		content.WriteString(zodHelpers + "\n")
+	}
+	content.WriteString(g.body.String())
+	return content.String(), nil
+}
+
+func (g *zodGenerator) p(str ...string) {
+	g.body.WriteString(strings.Join(str, "") + "\n")
+}
+
+// end terminates the statement of the last line.
+func (g *zodGenerator) end() {
+	body := strings.TrimSuffix(g.body.String(), "\n")
+	g.body.Reset()
+	g.body.WriteString(body + ";\n")
+}
+
+// schemaName returns the identifier of the schema of a message type, importing it if it belongs to another file.
+func (g *zodGenerator) schemaName(md protoreflect.MessageDescriptor) string {
+	if name, ok := g.names[md.FullName()]; ok {
+		return name
+	}
+	local := strings.TrimPrefix(string(md.FullName()), string(md.ParentFile().Package())+".")
+	name := strings.Replace(local, ".", "_", -1) + "Schema"
+	if g.used[name] {
+		name = strings.Replace(string(md.FullName()), ".", "_", -1) + "Schema"
+	}
+	g.used[name] = true
+	g.names[md.FullName()] = name
+	if md.ParentFile().Path() != g.file.Path() {
+		imported := strings.Replace(local, ".", "_", -1) + "Schema"
+		if imported != name {
+			imported += " as " + name
+		}
+		module := zodModule(g.file.Path(), md.ParentFile().Path())
+		g.imports[module] = append(g.imports[module], imported)
+	}
+	return name
+}
+
+// zodModule returns the module path of the file generated for the proto file other, relative to the one of from.
+func zodModule(from string, other string) string {
+	rel, err := filepath.Rel(path.Dir(from), strings.TrimSuffix(other, ".proto")+".zod")
+	if err != nil {
+		rel = strings.TrimSuffix(other, ".proto") + ".zod"
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// cyclicMessages returns the messages referencing themselves through their fields, whose schemas need an explicit type.
+func cyclicMessages(messages []protoreflect.MessageDescriptor) map[protoreflect.FullName]bool {
+	cyclic := map[protoreflect.FullName]bool{}
+	for _, md := range messages {
+		seen := map[protoreflect.FullName]bool{}
+		var reaches func(protoreflect.MessageDescriptor) bool
+		reaches = func(from protoreflect.MessageDescriptor) bool {
+			for i := 0; i < from.Fields().Len(); i++ {
+				fd := from.Fields().Get(i)
+				target := fd.Message()
+				if fd.IsMap() {
+					target = fd.MapValue().Message()
+				}
+				if target == nil || target.ParentFile().Path() != md.ParentFile().Path() {
+					continue
+				}
+				if target.FullName() == md.FullName() {
+					return true
+				}
+				if !seen[target.FullName()] {
+					seen[target.FullName()] = true
+					if reaches(target) {
+						return true
+					}
+				}
+			}
+			return false
+		}
+		cyclic[md.FullName()] = reaches(md)
+	}
+	return cyclic
+}
+
+func (g *zodGenerator) generateMessage(md protoreflect.MessageDescriptor, cyclic bool) error {
+	rules := descriptorRules(md)
+	name := g.schemaName(md)
+	if comment := comments(md); comment != "" {
+		g.p("/** ", strings.Replace(comment, "\n", "\n * ", -1), " */")
+	}
+	if cyclic {
+		g.p("export const ", name, ": z.ZodTypeAny = z")
+	} else {
+		g.p("export const ", name, " = z")
+	}
+	g.p("  .object({")
+	required := map[protoreflect.Name]bool{}
+	for _, fieldName := range rules.Message.GetRequired() {
+		required[protoreflect.Name(fieldName)] = true
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		expr, err := g.fieldSchema(fd, defaultRuleSets(rules.Fields[string(fd.Name())]), required[fd.Name()])
+		if err != nil {
+			return err
+		}
+		g.p("    ", jsString(fd.JSONName()), ": ", expr, ",")
+	}
+	g.p("  })")
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		var set []string
+		for j := 0; j < od.Fields().Len(); j++ {
+			set = append(set, zodIsSet(od.Fields().Get(j)))
+		}
+		count := "[" + strings.Join(set, ", ") + "].filter(Boolean).length"
+		g.p("  .refine((v) => ", count, " <= 1, { message: ", jsString("at most one of the fields of "+string(od.Name())+" can be set"), " })")
+		if rules.Oneofs[string(od.Name())].GetRequired() {
+			g.p("  .refine((v) => ", count, " === 1, { message: ", jsString("one of the fields of "+string(od.Name())+" must be set"), " })")
+		}
+	}
+	for _, group := range rules.Message.GetAtLeastOneOf() {
+		set, at, err := g.groupFields(md, group)
+		if err != nil {
+			return err
+		}
+		g.p("  .refine((v) => ", strings.Join(set, " || "), ", { message: \"at least one of the fields must be set\", path: [", jsString(at), "] })")
+	}
+	for _, group := range rules.Message.GetAtMostOneOf() {
+		set, at, err := g.groupFields(md, group)
+		if err != nil {
+			return err
+		}
+		g.p("  .refine((v) => [", strings.Join(set, ", "), "].filter(Boolean).length <= 1, { message: \"at most one of the fields can be set\", path: [", jsString(at), "] })")
+	}
+	if len(rules.Message.GetCel()) > 0 {
+		return fmt.Errorf("message %v: validator.message cel rules cannot be expressed with Zod", md.FullName())
+	}
+	g.end()
+	if !cyclic {
+		g.p("export type ", strings.TrimSuffix(name, "Schema"), " = z.infer<typeof ", name, ">;")
+	}
+	g.p()
+	return nil
+}
+
+// groupFields returns the expressions checking whether the fields of a group are set, and the field the violations are
+// reported on.
+func (g *zodGenerator) groupFields(md protoreflect.MessageDescriptor, group *validator.FieldGroup) ([]string, string, error) {
+	var set []string
+	for _, name := range group.GetFields() {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil, "", fmt.Errorf("message %v: field %v of a validator.message group does not exist", md.FullName(), name)
+		}
+		set = append(set, zodIsSet(fd))
+	}
+	at := group.GetName()
+	if fd := md.Fields().ByName(protoreflect.Name(at)); fd != nil {
+		at = fd.JSONName()
+	} else if at == "" && len(group.GetFields()) > 0 {
+		at = md.Fields().ByName(protoreflect.Name(group.GetFields()[0])).JSONName()
+	}
+	return set, at, nil
+}
+
+// zodIsSet returns the expression checking whether a field of the parsed object v is set, the fields without presence
+// being set when they don't hold their default value.
+func zodIsSet(fd protoreflect.FieldDescriptor) string {
+	v := "v[" + jsString(fd.JSONName()) + "]"
+	switch {
+	case fd.IsList():
+		return v + ".length > 0"
+	case fd.IsMap():
+		return "Object.keys(" + v + ").length > 0"
+	case fd.HasPresence():
+		return v + " !== undefined"
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return v + ` !== ""`
+	case protoreflect.BoolKind:
+		return v
+	case protoreflect.EnumKind:
+		return "(" + v + " !== 0 && " + v + " !== " + jsString(zeroEnumName(fd.Enum())) + ")"
+	}
+	return "Number(" + v + ") !== 0"
+}
+
+func zeroEnumName(ed protoreflect.EnumDescriptor) string {
+	if zero := ed.Values().ByNumber(0); zero != nil {
+		return string(zero.Name())
+	}
+	return ""
+}
+
+// fieldSchema returns the schema of a field in its object, optional for the fields with presence and defaulting to
+// the default value for the others, which protojson omits.
+func (g *zodGenerator) fieldSchema(fd protoreflect.FieldDescriptor, sets []*validator.FieldValidator, required bool) (string, error) {
+	for _, fv := range sets {
+		required = required || fv.GetRequired() || (fv.GetMsgExists() && fd.Message() != nil && !fd.IsList() && !fd.IsMap())
+	}
+	required = required || fd.Cardinality() == protoreflect.Required
+	var expr string
+	switch {
+	case fd.IsMap():
+		// the generated code doesn't validate map fields
+		value, err := g.valueSchema(fd.MapValue(), nil)
+		if err != nil {
+			return "", err
+		}
+		expr = "z.record(z.string(), " + value + ")"
+	case fd.IsList():
+		var collections, items []*validator.FieldValidator
+		for _, fv := range sets {
+			collection, item := repeatedRules(fd, fv, g.explicitItemRules)
+			collections = append(collections, collection)
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+		item, err := g.valueSchema(fd, items)
+		if err != nil {
+			return "", err
+		}
+		expr = "z.array(" + item + ")"
+		for _, fv := range collections {
+			checks, err := g.listChecks(fd, fv)
+			if err != nil {
+				return "", err
+			}
+			expr += checks
+		}
+	default:
+		value, err := g.valueSchema(fd, sets)
+		if err != nil {
+			return "", err
+		}
+		expr = value
+	}
+	switch {
+	case required:
+	case fd.HasPresence():
+		expr += ".optional()"
+	case fd.IsList():
+		expr += ".default([])"
+	case fd.IsMap():
+		expr += ".default({})"
+	default:
+		expr += ".default(" + zodDefault(fd) + ")"
+	}
+	return expr, nil
+}
+
+func zodDefault(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return `""`
+	case protoreflect.BoolKind:
+		return "false"
+	case protoreflect.EnumKind:
+		return jsString(zeroEnumName(fd.Enum()))
+	}
+	return "0"
+}
+
+// listChecks returns the checks of the rules of fv applying to a repeated field as a whole.
+func (g *zodGenerator) listChecks(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) (string, error) {
+	var checks string
+	message := zodMessage(fv)
+	if fv.RepeatedCountMin != nil {
+		checks += ".min(" + strconv.FormatInt(fv.GetRepeatedCountMin(), 10) + message + ")"
+	}
+	if fv.RepeatedCountMax != nil {
+		checks += ".max(" + strconv.FormatInt(fv.GetRepeatedCountMax(), 10) + message + ")"
+	}
+	if fv.LengthGt != nil {
+		checks += ".min(" + strconv.FormatInt(fv.GetLengthGt()+1, 10) + message + ")"
+	}
+	if fv.LengthLt != nil {
+		checks += ".max(" + strconv.FormatInt(fv.GetLengthLt()-1, 10) + message + ")"
+	}
+	if fv.LengthEq != nil {
+		checks += ".length(" + strconv.FormatInt(fv.GetLengthEq(), 10) + message + ")"
+	}
+	if fv.GetUnique() {
+		checks += ".refine((v) => new Set(v).size === v.length" + zodRefineMessage(fv, "elements must be unique") + ")"
+	}
+	if fv.UniqueBy != nil {
+		sub := fd.Message().Fields().ByName(protoreflect.Name(fv.GetUniqueBy()))
+		if sub == nil {
+			return "", fmt.Errorf("field %v: validator.unique_by field %v does not exist", fd.FullName(), fv.GetUniqueBy())
+		}
+		key := jsString(sub.JSONName())
+		checks += ".refine((v) => new Set(v.map((e) => e[" + key + "])).size === v.length" + zodRefineMessage(fv, "elements must have a unique "+sub.JSONName()) + ")"
+	}
+	if err := zodUnsupported(fd, fv); err != nil {
+		return "", err
+	}
+	return checks, nil
+}
+
+// valueSchema returns the schema of a single value of a field, checked by the rules of sets.
+func (g *zodGenerator) valueSchema(fd protoreflect.FieldDescriptor, sets []*validator.FieldValidator) (string, error) {
+	var expr string
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if wkt := zodWellKnown(fd.Message()); wkt != "" {
+			expr = wkt
+		} else if skipsNested(fd.Message()) {
+			expr = "z.record(z.string(), z.unknown())"
+		} else {
+			expr = "z.lazy(() => " + g.schemaName(fd.Message()) + ")"
+		}
+	case protoreflect.EnumKind:
+		expr = "z.union([z.string(), z.number().int()])"
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			expr = "z.null()"
+		}
+		for _, fv := range sets {
+			if fv.GetIsInEnum() {
+				var names []string
+				for i := 0; i < fd.Enum().Values().Len(); i++ {
+					names = append(names, jsString(string(fd.Enum().Values().Get(i).Name())))
+				}
+				expr = "z.enum([" + strings.Join(names, ", ") + "]" + zodMessage(fv) + ")"
+			}
+		}
+	default:
+		expr = zodScalar(fd.Kind())
+	}
+	for _, fv := range sets {
+		if err := zodUnsupported(fd, fv); err != nil {
+			return "", err
+		}
+		checks, err := g.valueChecks(fd, fv)
+		if err != nil {
+			return "", err
+		}
+		expr += checks
+	}
+	return expr, nil
+}
+
+func zodScalar(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "z.boolean()"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return "z.string()"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "z.number()"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson writes 64-bit integers as strings
+		return "z.union([z.number().int(), z.string().regex(/^-?[0-9]+$/)])"
+	}
+	return "z.number().int()"
+}
+
+// zodWellKnown returns the schema of the well-known types having a special protojson mapping, "" for other types.
+func zodWellKnown(md protoreflect.MessageDescriptor) string {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return "z.string().datetime({ offset: true })"
+	case "google.protobuf.Duration":
+		return `z.string().regex(/^-?[0-9]+(\.[0-9]{1,9})?s$/)`
+	case "google.protobuf.FieldMask":
+		return "z.string()"
+	case "google.protobuf.Struct":
+		return "z.record(z.string(), z.unknown())"
+	case "google.protobuf.ListValue":
+		return "z.array(z.unknown())"
+	case "google.protobuf.Value":
+		return "z.unknown()"
+	case "google.protobuf.Empty":
+		return "z.object({})"
+	case "google.protobuf.Any":
+		return `z.object({ "@type": z.string() }).passthrough()`
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return zodScalar(md.Fields().ByName("value").Kind())
+	}
+	return ""
+}
+
+// zodUnsupported fails for the rules of fv which cannot be checked by a Zod schema.
+func zodUnsupported(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) error {
+	var rule string
+	switch {
+	case len(fv.GetCel()) > 0:
+		rule = "cel"
+	case fv.RequiredIf != nil:
+		rule = "required_if"
+	case fv.RequiredUnless != nil:
+		rule = "required_unless"
+	case fv.ForbiddenIf != nil:
+		rule = "forbidden_if"
+	case fv.GetAnyValidate():
+		rule = "any_validate"
+	case fv.FieldMaskTarget != nil:
+		rule = "field_mask_target"
+	default:
+		return nil
+	}
+	return fmt.Errorf("field %v: validator.%s cannot be expressed with Zod", fd.FullName(), rule)
+}
+
+// valueChecks returns the checks of the rules of fv applying to a single value.
+func (g *zodGenerator) valueChecks(fd protoreflect.FieldDescriptor, fv *validator.FieldValidator) (string, error) {
+	var checks string
+	message := zodMessage(fv)
+	pattern := fv.Regex
+	if fv.UuidVer != nil {
+		uuid, err := getUUIDRegex(fv.UuidVer)
+		if err != nil {
+			return "", fmt.Errorf("field %v: %v", fd.FullName(), err)
+		}
+		pattern = &uuid
+	}
+	if pattern != nil {
+		re, warnings, err := jsRegex(*pattern)
+		if err != nil {
+			return "", fmt.Errorf("field %v: regex %q cannot be expressed in JavaScript: %v", fd.FullName(), *pattern, err)
+		}
+		for _, warning := range warnings {
+			log.Printf("WARNING: field %v: the JavaScript regex %s %s\n", fd.FullName(), re, warning)
+		}
+		checks += ".regex(" + re + message + ")"
+	}
+	switch {
+	case isIntKind(fd.Kind()) && is64Bit(fd.Kind()):
+		if fv.IntGt != nil {
+			checks += ".refine((v) => BigInt(v) > " + strconv.FormatInt(fv.GetIntGt(), 10) + "n" + zodRefineMessage(fv, fmt.Sprintf("must be greater than %d", fv.GetIntGt())) + ")"
+		}
+		if fv.IntLt != nil {
+			checks += ".refine((v) => BigInt(v) < " + strconv.FormatInt(fv.GetIntLt(), 10) + "n" + zodRefineMessage(fv, fmt.Sprintf("must be less than %d", fv.GetIntLt())) + ")"
+		}
+	case isIntKind(fd.Kind()):
+		if fv.IntGt != nil {
+			checks += ".gt(" + strconv.FormatInt(fv.GetIntGt(), 10) + message + ")"
+		}
+		if fv.IntLt != nil {
+			checks += ".lt(" + strconv.FormatInt(fv.GetIntLt(), 10) + message + ")"
+		}
+	case fd.Kind() == protoreflect.FloatKind || fd.Kind() == protoreflect.DoubleKind:
+		if fv.FloatGt != nil {
+			checks += ".gt(" + string(floatNumber(fv.GetFloatGt()-fv.GetFloatEpsilon())) + message + ")"
+		}
+		if fv.FloatGte != nil {
+			checks += ".gte(" + string(floatNumber(fv.GetFloatGte())) + message + ")"
+		}
+		if fv.FloatLt != nil {
+			checks += ".lt(" + string(floatNumber(fv.GetFloatLt()+fv.GetFloatEpsilon())) + message + ")"
+		}
+		if fv.FloatLte != nil {
+			checks += ".lte(" + string(floatNumber(fv.GetFloatLte())) + message + ")"
+		}
+	case fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind:
+		if fv.GetStringNotEmpty() {
+			checks += ".min(1" + message + ")"
+		}
+		// the generated code compares the number of bytes, of the UTF-8 string or of the decoded base64
+		length := "byteLength"
+		if fd.Kind() == protoreflect.BytesKind {
+			length = "base64Length"
+		}
+		if fv.LengthGt != nil {
+			g.helpers = true
+			checks += ".refine((v) => " + length + "(v) > " + strconv.FormatInt(fv.GetLengthGt(), 10) + zodRefineMessage(fv, fmt.Sprintf("must have a length greater than %d", fv.GetLengthGt())) + ")"
+		}
+		if fv.LengthLt != nil {
+			g.helpers = true
+			checks += ".refine((v) => " + length + "(v) < " + strconv.FormatInt(fv.GetLengthLt(), 10) + zodRefineMessage(fv, fmt.Sprintf("must have a length smaller than %d", fv.GetLengthLt())) + ")"
+		}
+		if fv.LengthEq != nil {
+			g.helpers = true
+			checks += ".refine((v) => " + length + "(v) === " + strconv.FormatInt(fv.GetLengthEq(), 10) + zodRefineMessage(fv, fmt.Sprintf("must have a length equal to %d", fv.GetLengthEq())) + ")"
+		}
+	case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Any":
+		if len(fv.GetAnyIn()) > 0 {
+			checks += ".refine((v) => [" + strings.Join(jsStrings(validator.AnyTypeURLs(fv.GetAnyIn())), ", ") + "].includes(v[\"@type\"])" + zodRefineMessage(fv, "must have one of the allowed types") + ")"
+		}
+		if len(fv.GetAnyNotIn()) > 0 {
+			checks += ".refine((v) => ![" + strings.Join(jsStrings(validator.AnyTypeURLs(fv.GetAnyNotIn())), ", ") + "].includes(v[\"@type\"])" + zodRefineMessage(fv, "must not have one of the denied types") + ")"
+		}
+	}
+	return checks, nil
+}
+
+// zodMessage returns the parameter setting the message of a built-in check to the human_error of fv, if any.
+func zodMessage(fv *validator.FieldValidator) string {
+	if fv.HumanError == nil {
+		return ""
+	}
+	return ", { message: " + jsString(fv.GetHumanError()) + " }"
+}
+
+// zodRefineMessage returns the parameter setting the message of a refinement, the human_error of fv if any.
+func zodRefineMessage(fv *validator.FieldValidator, message string) string {
+	if fv.HumanError != nil {
+		message = fv.GetHumanError()
+	}
+	return ", { message: " + jsString(message) + " }"
+}
+
+func isIntKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.Uint32Kind,
+		protoreflect.Fixed32Kind, protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+func is64Bit(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind,
+		protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+func skipsNested(md protoreflect.MessageDescriptor) bool {
+	rules := validator.DescriptorRules(md)
+	return rules.Message.GetSkipNested() || rules.Message.GetDisabled()
+}
+
+// jsString returns a JavaScript string literal, JSON strings being valid ones.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func jsStrings(values []string) []string {
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		literals = append(literals, jsString(v))
+	}
+	return literals
+}
+
+var (
+	jsQuantifier   = regexp.MustCompile(`^\{[0-9]+(,[0-9]*)?\}`)
+	re2Flags       = regexp.MustCompile(`^\(\?([a-zA-Z]+)\)`)
+	generalUnicode = map[string]bool{
+		"L": true, "Lu": true, "Ll": true, "Lt": true, "Lm": true, "Lo": true,
+		"M": true, "Mn": true, "Mc": true, "Me": true,
+		"N": true, "Nd": true, "Nl": true, "No": true,
+		"P": true, "Pc": true, "Pd": true, "Ps": true, "Pe": true, "Pi": true, "Pf": true, "Po": true,
+		"S": true, "Sm": true, "Sc": true, "Sk": true, "So": true,
+		"Z": true, "Zs": true, "Zl": true, "Zp": true,
+		"C": true, "Cc": true, "Cf": true, "Cs": true, "Co": true,
+	}
+	posixClasses = map[string]string{
+		"alnum":  `0-9A-Za-z`,
+		"alpha":  `A-Za-z`,
+		"ascii":  `\x00-\x7F`,
+		"blank":  `\t `,
+		"cntrl":  `\x00-\x1F\x7F`,
+		"digit":  `0-9`,
+		"graph":  `!-~`,
+		"lower":  `a-z`,
+		"print":  ` -~`,
+		"punct":  `!-\/:-@\[-\x60{-~`,
+		"space":  `\t\n\v\f\r `,
+		"upper":  `A-Z`,
+		"word":   `0-9A-Za-z_`,
+		"xdigit": `0-9A-Fa-f`,
+	}
+)
+
+// jsRegex translates a RE2 regex to a JavaScript regex literal with the unicode flag, which matches code points like
+// RE2. It returns warnings for the constructs which behave slightly differently, and fails for those JavaScript
+// doesn't support.
+func jsRegex(re string) (string, []string, error) {
+	if _, err := syntax.Parse(re, syntax.Perl); err != nil {
+		return "", nil, err
+	}
+	var warnings []string
+	flags := ""
+	if m := re2Flags.FindStringSubmatch(re); m != nil {
+		for _, flag := range m[1] {
+			if flag != 'i' && flag != 's' && flag != 'm' {
+				return "", nil, fmt.Errorf("the flag %c is not supported", flag)
+			}
+			if !strings.ContainsRune(flags, flag) {
+				flags += string(flag)
+			}
+		}
+		re = re[len(m[0]):]
+	}
+	var out strings.Builder
+	inClass := false
+	for i := 0; i < len(re); {
+		c := re[i]
+		switch {
+		case c == '\\':
+			n, err := jsEscape(&out, re[i:], inClass, &warnings)
+			if err != nil {
+				return "", nil, err
+			}
+			i += n
+			continue
+		case c == '[' && !inClass:
+			inClass = true
+			out.WriteByte('[')
+			i++
+			if i < len(re) && re[i] == '^' {
+				out.WriteByte('^')
+				i++
+			}
+			if i < len(re) && re[i] == ']' {
+				// a leading ] is a literal in RE2, JavaScript would read an empty class
+				out.WriteString(`\]`)
+				i++
+			}
+			continue
+		case c == '[' && strings.HasPrefix(re[i:], "[:"):
+			end := strings.Index(re[i:], ":]")
+			name := re[i+2 : i+end]
+			class, ok := posixClasses[name]
+			if !ok {
+				return "", nil, fmt.Errorf("the class [:%s:] is not supported", name)
+			}
+			out.WriteString(class)
+			i += end + 2
+			continue
+		case c == '[':
+			out.WriteString(`\[`)
+		case c == ']' && inClass:
+			inClass = false
+			out.WriteByte(']')
+		case (c == ']' || c == '}') && !inClass:
+			out.WriteString(`\` + string(c))
+		case c == '{' && !inClass:
+			if q := jsQuantifier.FindString(re[i:]); q != "" {
+				out.WriteString(q)
+				i += len(q)
+				continue
+			}
+			out.WriteString(`\{`)
+		case c == '(' && !inClass && strings.HasPrefix(re[i:], "(?P<"):
+			out.WriteString("(?<")
+			i += 4
+			continue
+		case c == '(' && !inClass && strings.HasPrefix(re[i:], "(?") && !strings.HasPrefix(re[i:], "(?:"):
+			return "", nil, fmt.Errorf("inline flags are only supported at the start of the regex")
+		case c == '/':
+			out.WriteString(`\/`)
+		default:
+			r, size := utf8.DecodeRuneInString(re[i:])
+			out.WriteRune(r)
+			i += size
+			continue
+		}
+		i++
+	}
+	return "/" + out.String() + "/u" + flags, warnings, nil
+}
+
+// jsEscape writes the JavaScript equivalent of the RE2 escape sequence starting re, returning its length.
+func jsEscape(out *strings.Builder, re string, inClass bool, warnings *[]string) (int, error) {
+	if len(re) < 2 {
+		return 0, fmt.Errorf("trailing backslash")
+	}
+	c := re[1]
+	switch {
+	case c == 'A' && !inClass:
+		out.WriteByte('^')
+	case c == 'z' && !inClass:
+		out.WriteByte('$')
+	case c == 'Q':
+		end := strings.Index(re, `\E`)
+		literal := re[2:]
+		if end >= 0 {
+			literal = re[2:end]
+		}
+		for _, r := range literal {
+			if strings.ContainsRune(`^$\.*+?()[]{}|/-`, r) {
+				out.WriteByte('\\')
+			}
+			out.WriteRune(r)
+		}
+		if end < 0 {
+			return len(re), nil
+		}
+		return end + 2, nil
+	case c == 'p' || c == 'P':
+		name, n := "", 0
+		if len(re) > 2 && re[2] == '{' {
+			end := strings.IndexByte(re, '}')
+			name, n = re[3:end], end+1
+		} else {
+			name, n = re[2:3], 3
+		}
+		negated := strings.HasPrefix(name, "^")
+		name = strings.TrimPrefix(name, "^")
+		if name != "Any" && !generalUnicode[name] {
+			name = "Script=" + name
+		}
+		if negated {
+			c ^= 'p' ^ 'P'
+		}
+		out.WriteString(`\` + string(c) + "{" + name + "}")
+		return n, nil
+	case c == 'x':
+		if len(re) > 2 && re[2] == '{' {
+			end := strings.IndexByte(re, '}')
+			out.WriteString(`\u{` + re[3:end] + `}`)
+			return end + 1, nil
+		}
+		out.WriteString(re[:4])
+		return 4, nil
+	case c >= '0' && c <= '7':
+		n := 2
+		for n < len(re) && n < 4 && re[n] >= '0' && re[n] <= '7' {
+			n++
+		}
+		v, _ := strconv.ParseUint(re[1:n], 8, 8)
+		fmt.Fprintf(out, `\x%02X`, v)
+		return n, nil
+	case c == 'a':
+		out.WriteString(`\x07`)
+	case c == 's' || c == 'S':
+		out.WriteString(re[:2])
+		if !containsString(*warnings, "matches the Unicode spaces with \\s, RE2 only the ASCII ones") {
+			*warnings = append(*warnings, "matches the Unicode spaces with \\s, RE2 only the ASCII ones")
+		}
+	case strings.IndexByte("dDwWbBfnrtv", c) >= 0:
+		out.WriteString(re[:2])
+	case c < utf8.RuneSelf && (('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')):
+		return 0, fmt.Errorf("the escape sequence \\%c is not supported", c)
+	case strings.IndexByte(`^$\.*+?()[]{}|/`, c) >= 0 || (c == '-' && inClass):
+		out.WriteString(re[:2])
+	default:
+		// JavaScript forbids escaping the other characters with the unicode flag
+		r, size := utf8.DecodeRuneInString(re[1:])
+		out.WriteRune(r)
+		return 1 + size, nil
+	}
+	return 2, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -11,6 +11,9 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+
 	validatorplugin "github.com/monstrum/go-proto-validators/plugin"
 )
 
@@ -30,29 +33,80 @@ func main() {
 		gen.Fail("no files to generate")
 	}
 
-	useGogoImport := false
+	opts := validatorplugin.Options{}
+	mode := "go"
 	// Match parsing algorithm from Generator.CommandLineParameters
 	for _, parameter := range strings.Split(gen.Request.GetParameter(), ",") {
 		kvp := strings.SplitN(parameter, "=", 2)
-		// We only care about key-value pairs where the key is "gogoimport"
-		if len(kvp) != 2 || kvp[0] != "gogoimport" {
+		// We only care about key-value pairs of our own options
+		if len(kvp) != 2 {
 			continue
 		}
-		useGogoImport, err = strconv.ParseBool(kvp[1])
-		if err != nil {
-			gen.Error(err, "parsing gogoimport option")
+		switch kvp[0] {
+		case "gogoimport":
+			opts.UseGogoImport, err = strconv.ParseBool(kvp[1])
+			if err != nil {
+				gen.Error(err, "parsing gogoimport option")
+			}
+		case "mode":
+			mode = kvp[1]
+		case "openapi_format":
+			opts.OpenAPIFormat = kvp[1]
+		case "openapi_merge":
+			opts.OpenAPIMerge = kvp[1]
+		case "doc_format":
+			opts.DocFormat = kvp[1]
+		case "sql_naming":
+			opts.SQLNaming = kvp[1]
+		case "sql_enums":
+			opts.SQLEnums = kvp[1]
+		case "explicit_item_rules":
+			opts.ExplicitItemRules, err = strconv.ParseBool(kvp[1])
+			if err != nil {
+				gen.Error(err, "parsing explicit_item_rules option")
+			}
 		}
 	}
 
-	gen.CommandLineParameters(gen.Request.GetParameter())
+	switch mode {
+	case "go":
+		gen.CommandLineParameters(gen.Request.GetParameter())
 
-	gen.WrapTypes()
-	gen.SetPackageNames()
-	gen.BuildTypeNameMap()
-	gen.GeneratePlugin(validatorplugin.NewPlugin(useGogoImport))
+		gen.WrapTypes()
+		gen.SetPackageNames()
+		gen.BuildTypeNameMap()
+		gen.GeneratePlugin(validatorplugin.NewPluginWithOptions(opts))
 
-	for i := 0; i < len(gen.Response.File); i++ {
-		gen.Response.File[i].Name = proto.String(strings.Replace(*gen.Response.File[i].Name, ".pb.go", ".validator.pb.go", -1))
+		for i := 0; i < len(gen.Response.File); i++ {
+			gen.Response.File[i].Name = proto.String(strings.Replace(*gen.Response.File[i].Name, ".pb.go", ".validator.pb.go", -1))
+		}
+	case "jsonschema":
+		gen.Response, err = validatorplugin.GenerateJSONSchema(gen.Request, opts)
+		if err != nil {
+			gen.Error(err, "generating JSON Schemas")
+		}
+	case "openapi":
+		gen.Response, err = validatorplugin.GenerateOpenAPI(gen.Request, opts)
+		if err != nil {
+			gen.Error(err, "generating OpenAPI documents")
+		}
+	case "zod":
+		gen.Response, err = validatorplugin.GenerateZod(gen.Request, opts)
+		if err != nil {
+			gen.Error(err, "generating Zod schemas")
+		}
+	case "doc":
+		gen.Response, err = validatorplugin.GenerateDoc(gen.Request, opts)
+		if err != nil {
+			gen.Error(err, "generating documentation")
+		}
+	case "sql":
+		gen.Response, err = validatorplugin.GenerateSQL(gen.Request, opts)
+		if err != nil {
+			gen.Error(err, "generating SQL constraints")
+		}
+	default:
+		gen.Fail("unknown mode", mode)
 	}
 
 	// Send back the results.
@@ -60,6 +114,16 @@ func main() {
 	if err != nil {
 		gen.Error(err, "failed to marshal output proto")
 	}
+	// gogo's response has no supported_features, protoc refuses to generate proto3 optional fields without it
+	resp := &pluginpb.CodeGeneratorResponse{}
+	if err := protov2.Unmarshal(data, resp); err != nil {
+		gen.Error(err, "failed to convert output proto")
+	}
+	resp.SupportedFeatures = protov2.Uint64(uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL))
+	data, err = protov2.Marshal(resp)
+	if err != nil {
+		gen.Error(err, "failed to marshal output proto")
+	}
 	_, err = os.Stdout.Write(data)
 	if err != nil {
 		gen.Error(err, "failed to write output proto")
@@ -0,0 +1,1050 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ValidateReflect validates all fields of m like the generated ValidateAll, reading the validator options from the
+// descriptors instead of relying on generated code. It validates messages only known at runtime, such as dynamicpb
+// messages. The Groups, Observer and limits of opts are honoured.
+func ValidateReflect(m protoreflect.Message, opts ...Option) error {
+	_, options := withOptions(context.Background(), opts)
+	observer := options.Observer
+	if observer == nil {
+		observer = ObserverFromContext(context.Background())
+	}
+	err := observe(observer, m.Interface(), func() error {
+		validations, err := validateReflect(m, options, nil)
+		if err != nil {
+			return err
+		}
+		return options.limits.truncate(validations)
+	})
+	if validations, ok := err.(*ValidationErrors); ok {
+		return validations.Err()
+	}
+	return err
+}
+
+// validateReflect collects the errors and warnings of the fields of m listed in mask, returning an error if the rules of
+// its type are invalid.
+func validateReflect(m protoreflect.Message, options *Options, mask ValidationMask) (*ValidationErrors, error) {
+	validations := &ValidationErrors{limits: options.limits}
+	if !m.IsValid() {
+		return validations, nil
+	}
+	rules, err := reflectRulesOf(m.Descriptor())
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.validate(m, validations, options, mask); err != nil {
+		return nil, err
+	}
+	return validations, nil
+}
+
+// validateNestedReflect validates the nested message m like validateReflect, within the MaxDepth limit.
+func validateNestedReflect(m protoreflect.Message, options *Options, mask ValidationMask) (*ValidationErrors, error) {
+	if !options.limits.enter() {
+		return &ValidationErrors{limits: options.limits}, nil
+	}
+	defer options.limits.leave()
+	return validateReflect(m, options, mask)
+}
+
+// reflectRules are the rules of a message type, as read from its descriptor.
+type reflectRules struct {
+	md       protoreflect.MessageDescriptor
+	disabled bool
+	message  *MessageValidator
+	fields   []*reflectField
+	oneofs   []protoreflect.OneofDescriptor
+	cel      *CELValidator
+}
+
+type reflectField struct {
+	fd   protoreflect.FieldDescriptor
+	name string
+	sets []*reflectRuleSet
+}
+
+// reflectRuleSet is one of the validators of a field: the validator set on the field itself or one of its
+// group_rules.
+type reflectRuleSet struct {
+	groups []string
+	fv     *FieldValidator
+	// loop are the rules checked along with the nested validation, nil if there is none.
+	loop *FieldValidator
+	// implicit is set when loop is applied to each element of a repeated field, see ImplicitItemRules.
+	implicit bool
+	regex    *regexp.Regexp
+}
+
+type reflectRulesEntry struct {
+	md    protoreflect.MessageDescriptor
+	rules *reflectRules
+	err   error
+}
+
+// reflectRulesKey identifies a message type by its file and full name rather than by its descriptor, so that the
+// descriptors built again for the same type, such as those of dynamicpb messages, don't grow the cache.
+type reflectRulesKey struct {
+	file     string
+	fullName protoreflect.FullName
+}
+
+// reflectRulesCache holds the rules of the message types validated so far, one entry per type. An entry is replaced
+// when a type is validated with a different descriptor.
+var reflectRulesCache sync.Map
+
+func reflectRulesOf(md protoreflect.MessageDescriptor) (*reflectRules, error) {
+	key := reflectRulesKey{md.ParentFile().Path(), md.FullName()}
+	if value, ok := reflectRulesCache.Load(key); ok {
+		if entry := value.(*reflectRulesEntry); entry.md == md {
+			return entry.rules, entry.err
+		}
+	}
+	rules, err := newReflectRules(md)
+	reflectRulesCache.Store(key, &reflectRulesEntry{md, rules, err})
+	return rules, err
+}
+
+func newReflectRules(md protoreflect.MessageDescriptor) (*reflectRules, error) {
+	mv, _ := optionsExtension(md.Options(), E_Message).(*MessageValidator)
+	rules := &reflectRules{md: md, disabled: mv.GetDisabled(), message: mv}
+	if rules.disabled {
+		return rules, nil
+	}
+	var celRules []*CELRule
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		field := &reflectField{fd: fd, name: goName(string(fd.Name()))}
+		rules.fields = append(rules.fields, field)
+		fv, _ := optionsExtension(fd.Options(), E_Field).(*FieldValidator)
+		if fv == nil {
+			continue
+		}
+		field.sets = append(field.sets, &reflectRuleSet{groups: fv.GetGroups(), fv: fv})
+		for _, groupRules := range fv.GetGroupRules() {
+			field.sets = append(field.sets, &reflectRuleSet{groups: groupRules.GetGroups(), fv: groupRules})
+		}
+		for _, set := range field.sets {
+			set.fv = proto.Clone(set.fv).(*FieldValidator)
+			set.fv.Groups, set.fv.GroupRules = nil, nil
+			if set.fv.Items != nil && set.fv.Items.Severity == nil {
+				set.fv.Items.Severity = set.fv.Severity
+			}
+			set.loop = ValueRules(set.fv)
+			if err := set.compile(fd); err != nil {
+				return nil, fmt.Errorf("field %s: %v", fd.FullName(), err)
+			}
+			for _, c := range set.fv.GetCel() {
+				groups := c.GetGroups()
+				if len(groups) == 0 {
+					groups = set.groups
+				}
+				warning := set.fv.GetSeverity() == Severity_SEVERITY_WARNING
+				if c.Severity != nil {
+					warning = c.GetSeverity() == Severity_SEVERITY_WARNING
+				}
+				celRules = append(celRules, &CELRule{
+					Field:      field.name,
+					ProtoName:  string(fd.Name()),
+					Id:         c.GetId(),
+					Message:    c.GetMessage(),
+					Expression: c.GetExpression(),
+					Groups:     groups,
+					Warning:    warning,
+				})
+			}
+			if err := rules.checkConditions(fd, set.fv); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, c := range mv.GetCel() {
+		celRules = append(celRules, &CELRule{
+			Id:         c.GetId(),
+			Message:    c.GetMessage(),
+			Expression: c.GetExpression(),
+			Groups:     c.GetGroups(),
+			Warning:    c.GetSeverity() == Severity_SEVERITY_WARNING,
+		})
+	}
+	if len(celRules) > 0 {
+		rules.cel = NewCELValidator(celRules...)
+	}
+	for _, name := range mv.GetRequired() {
+		if _, err := rules.field(name, "validator.message required"); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range append(mv.GetAtLeastOneOf(), mv.GetAtMostOneOf()...) {
+		for _, name := range group.GetFields() {
+			if _, err := rules.field(name, "validator.message field group"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if md.ParentFile().Syntax() != protoreflect.Proto2 {
+		for i := 0; i < md.Oneofs().Len(); i++ {
+			od := md.Oneofs().Get(i)
+			if ov, _ := optionsExtension(od.Options(), E_Oneof).(*OneofValidator); ov.GetRequired() {
+				rules.oneofs = append(rules.oneofs, od)
+			}
+		}
+	}
+	return rules, nil
+}
+
+func (s *reflectRuleSet) compile(fd protoreflect.FieldDescriptor) error {
+	values := s.loop
+	if fd.IsList() {
+		s.implicit = ImplicitItemRules(fd.Kind(), s.loop)
+		if !s.implicit {
+			items, err := ItemRules(fd.Kind(), s.loop)
+			if err != nil {
+				return err
+			}
+			values = items
+		}
+	}
+	if fd.Kind() != protoreflect.StringKind || values == nil || (values.Regex == nil && values.UuidVer == nil) {
+		return nil
+	}
+	pattern := values.GetRegex()
+	if values.UuidVer != nil {
+		uuid, err := UUIDRegex(values.GetUuidVer())
+		if err != nil {
+			return err
+		}
+		pattern = uuid
+		values.Regex = &pattern
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	s.regex = regex
+	return nil
+}
+
+// field looks up a field referenced by name from a rule.
+func (r *reflectRules) field(name string, rule string) (protoreflect.FieldDescriptor, error) {
+	fd := r.md.Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		return nil, fmt.Errorf("field %s referenced by %s does not exist in %s", name, rule, r.md.FullName())
+	}
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		return nil, fmt.Errorf("field %s.%s is part of a oneof and cannot be used in %s", r.md.FullName(), name, rule)
+	}
+	return fd, nil
+}
+
+func (r *reflectRules) checkConditions(fd protoreflect.FieldDescriptor, fv *FieldValidator) error {
+	for _, condition := range []*FieldCondition{fv.GetRequiredIf(), fv.GetRequiredUnless(), fv.GetForbiddenIf()} {
+		if condition == nil {
+			continue
+		}
+		if _, err := r.field(string(fd.Name()), "validator.field required_if/required_unless/forbidden_if"); err != nil {
+			return err
+		}
+		sibling, err := r.field(condition.GetField(), "validator.field condition")
+		if err != nil {
+			return err
+		}
+		if len(condition.GetIn()) > 0 && (sibling.IsList() || sibling.IsMap() || sibling.Message() != nil || sibling.Kind() == protoreflect.BytesKind) {
+			return fmt.Errorf("field %s can only be checked for presence, values are not supported", sibling.FullName())
+		}
+		for _, value := range condition.GetIn() {
+			if _, err := conditionValue(sibling, value); err != nil {
+				return fmt.Errorf("value %q of the condition on %s: %v", value, sibling.FullName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *reflectRules) validate(m protoreflect.Message, validations *ValidationErrors, options *Options, mask ValidationMask) error {
+	if r.disabled {
+		return nil
+	}
+	groups := options.Groups
+	if InGroups(groups) {
+		r.validateMessageRules(m, validations, mask)
+	}
+	for _, field := range r.fields {
+		if !mask.Lists(string(field.fd.Name())) {
+			continue
+		}
+		for _, set := range field.sets {
+			if InGroups(groups, set.groups...) {
+				r.validateConditions(m, field, set.fv, validations)
+			}
+		}
+	}
+	if InGroups(groups) {
+		for _, od := range r.oneofs {
+			if m.WhichOneof(od) == nil && mask.Lists(fieldNames(od.Fields())...) {
+				validations.AddValidationError(goName(string(od.Name())), "one_of", OneofRequiredMessage)
+			}
+		}
+	}
+	for _, field := range r.fields {
+		if !mask.Lists(string(field.fd.Name())) {
+			continue
+		}
+		nested := field.fd.Message() != nil && !skipsNested(field.fd.Message())
+		for _, set := range field.sets {
+			if !InGroups(groups, set.groups...) {
+				continue
+			}
+			if err := r.validateField(m, field, set, nested, validations, options, mask.field(field.fd.Name())); err != nil {
+				return err
+			}
+			nested = false
+		}
+		if nested {
+			if err := r.validateField(m, field, &reflectRuleSet{}, true, validations, options, mask.field(field.fd.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	if r.cel != nil {
+		r.cel.ValidateAllMask(m.Interface(), validations, groups, mask)
+	}
+	return nil
+}
+
+func (r *reflectRules) validateMessageRules(m protoreflect.Message, validations *ValidationErrors, mask ValidationMask) {
+	for _, name := range r.message.GetRequired() {
+		fd := r.md.Fields().ByName(protoreflect.Name(name))
+		if !m.Has(fd) && mask.Lists(name) {
+			validations.AddValidationError(goName(name), "required", "value must be set")
+		}
+	}
+	for _, group := range r.message.GetAtLeastOneOf() {
+		if r.countSet(m, group) == 0 && mask.Lists(group.GetFields()...) {
+			validations.AddValidationError(fieldGroupName(group), "at_least_one_of", fmt.Sprintf("one of the fields %s must be set", strings.Join(group.GetFields(), ", ")))
+		}
+	}
+	for _, group := range r.message.GetAtMostOneOf() {
+		if r.countSet(m, group) > 1 && mask.Lists(group.GetFields()...) {
+			validations.AddValidationError(fieldGroupName(group), "at_most_one_of", fmt.Sprintf("at most one of the fields %s can be set", strings.Join(group.GetFields(), ", ")))
+		}
+	}
+}
+
+func (r *reflectRules) countSet(m protoreflect.Message, group *FieldGroup) int {
+	set := 0
+	for _, name := range group.GetFields() {
+		if m.Has(r.md.Fields().ByName(protoreflect.Name(name))) {
+			set++
+		}
+	}
+	return set
+}
+
+// fieldNames returns the names of fields.
+func fieldNames(fields protoreflect.FieldDescriptors) []string {
+	names := make([]string, fields.Len())
+	for i := range names {
+		names[i] = string(fields.Get(i).Name())
+	}
+	return names
+}
+
+func fieldGroupName(group *FieldGroup) string {
+	if group.GetName() != "" {
+		return group.GetName()
+	}
+	if len(group.GetFields()) == 0 {
+		return ""
+	}
+	return goName(group.GetFields()[0])
+}
+
+func (r *reflectRules) validateConditions(m protoreflect.Message, field *reflectField, fv *FieldValidator, validations *ValidationErrors) {
+	isSet := m.Has(field.fd)
+	for _, rule := range []struct {
+		violation string
+		condition *FieldCondition
+	}{
+		{"required_if", fv.GetRequiredIf()},
+		{"required_unless", fv.GetRequiredUnless()},
+		{"forbidden_if", fv.GetForbiddenIf()},
+	} {
+		if rule.condition == nil {
+			continue
+		}
+		holds, description := r.conditionHolds(m, rule.condition)
+		var violated bool
+		var errorStr string
+		switch rule.violation {
+		case "required_if":
+			violated, errorStr = holds && !isSet, "value must be set when "+description
+		case "required_unless":
+			violated, errorStr = !holds && !isSet, "value must be set unless "+description
+		case "forbidden_if":
+			violated, errorStr = holds && isSet, "value must not be set when "+description
+		}
+		if !violated {
+			continue
+		}
+		if fv.GetSeverity() == Severity_SEVERITY_WARNING {
+			validations.AddValidationWarning(field.name, rule.violation, errorStr)
+		} else {
+			validations.AddValidationError(field.name, rule.violation, errorStr)
+		}
+	}
+}
+
+// conditionHolds reports whether a validator.FieldCondition holds, along with its description for error messages.
+func (r *reflectRules) conditionHolds(m protoreflect.Message, condition *FieldCondition) (bool, string) {
+	sibling := r.md.Fields().ByName(protoreflect.Name(condition.GetField()))
+	if len(condition.GetIn()) == 0 {
+		return m.Has(sibling), condition.GetField() + " is set"
+	}
+	description := condition.GetField() + " is " + strings.Join(condition.GetIn(), " or ")
+	if sibling.HasPresence() && !m.Has(sibling) && r.md.ParentFile().Syntax() == protoreflect.Proto2 {
+		return false, description
+	}
+	value := m.Get(sibling)
+	for _, in := range condition.GetIn() {
+		expected, _ := conditionValue(sibling, in)
+		if scalarEqual(sibling, value, expected) {
+			return true, description
+		}
+	}
+	return false, description
+}
+
+// conditionValue parses the text of a scalar value of a field.
+func conditionValue(fd protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if n, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		if v := fd.Enum().Values().ByName(protoreflect.Name(value)); v != nil {
+			return protoreflect.ValueOfEnum(v.Number()), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("not a value of %s", fd.Enum().FullName())
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Uint32Kind, protoreflect.Uint64Kind,
+		protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.FloatKind, protoreflect.DoubleKind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		f, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field type")
+}
+
+// scalarEqual compares the value of a field to a value returned by conditionValue.
+func scalarEqual(fd protoreflect.FieldDescriptor, value protoreflect.Value, expected protoreflect.Value) bool {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return value.Enum() == expected.Enum()
+	case protoreflect.StringKind:
+		return value.String() == expected.String()
+	case protoreflect.BoolKind:
+		return value.Bool() == expected.Bool()
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		return value.Int() == expected.Int()
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return expected.Int() >= 0 && value.Uint() == uint64(expected.Int())
+	case protoreflect.FloatKind:
+		return float32(value.Float()) == float32(expected.Float())
+	}
+	return floatValue(fd, value) == expected.Float()
+}
+
+func (r *reflectRules) validateField(m protoreflect.Message, field *reflectField, set *reflectRuleSet, nested bool, validations *ValidationErrors, options *Options, mask ValidationMask) error {
+	fd := field.fd
+	fv := set.loop
+	if (fv == nil && !nested) || fd.IsMap() {
+		// map fields are not validated by the generated code either.
+		return nil
+	}
+	proto2 := fd.ParentFile().Syntax() == protoreflect.Proto2
+	if fd.ContainingOneof() != nil && !m.Has(fd) {
+		return nil
+	}
+	values := fv
+	if fd.IsList() && !set.implicit {
+		values = fv.GetItems()
+	}
+	isAny := fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Any"
+	nested = nested || (isAny && withAnyConstraint(values))
+	if !fd.IsList() {
+		if proto2 && fd.HasPresence() && !m.Has(fd) && (fd.Message() != nil || isNullable(fd)) {
+			return nil
+		}
+		if proto2 && fd.Cardinality() == protoreflect.Required && fd.Kind() == protoreflect.BoolKind {
+			// bool fields have no rules checked along with their value.
+			return nil
+		}
+		return r.validateValue(m.Get(fd), m.Has(fd), field, set, fv, nested, "message", validations, options, mask)
+	}
+	list := m.Get(fd).List()
+	r.validateList(list, field, set, validations)
+	if values == nil && !nested {
+		return nil
+	}
+	for i := 0; i < list.Len(); i++ {
+		if !validations.Inspect(i) {
+			break
+		}
+		item := list.Get(i)
+		present := fd.Message() == nil || item.Message().IsValid()
+		errors, warnings := len(validations.Errors), len(validations.Warnings)
+		if err := r.validateValue(item, present, field, set, values, nested, "array", validations, options, mask); err != nil {
+			return err
+		}
+		markElement(validations, errors, warnings, i)
+	}
+	return nil
+}
+
+// markElement marks the violations added after the given numbers of errors and warnings as violations of the element
+// i of a repeated field.
+func markElement(validations *ValidationErrors, errors, warnings int, i int) {
+	for _, err := range validations.Errors[errors:] {
+		err.Index, err.Element = i, true
+	}
+	for _, err := range validations.Warnings[warnings:] {
+		err.Index, err.Element = i, true
+	}
+}
+
+// validateList checks the rules of a repeated field on the field itself.
+func (r *reflectRules) validateList(list protoreflect.List, field *reflectField, set *reflectRuleSet, validations *ValidationErrors) {
+	fv := set.loop
+	if fv == nil {
+		return
+	}
+	var elements []interface{}
+	if fv.RepeatedCountMin != nil || fv.RepeatedCountMax != nil || fv.LengthGt != nil || fv.LengthLt != nil || fv.LengthEq != nil {
+		// the generated code formats the whole slice
+		elements = make([]interface{}, list.Len())
+		for i := range elements {
+			elements[i] = formatValue(field.fd, list.Get(i))
+		}
+	}
+	if fv.RepeatedCountMin != nil && int64(list.Len()) < fv.GetRepeatedCountMin() {
+		addReflectError(validations, field.name, "repeated_count_min", fmt.Sprint(`contain at least `, fv.GetRepeatedCountMin(), ` elements`), elements, fv)
+	}
+	if fv.RepeatedCountMax != nil && int64(list.Len()) > fv.GetRepeatedCountMax() {
+		addReflectError(validations, field.name, "repeated_count_max", fmt.Sprint(`contain at most `, fv.GetRepeatedCountMax(), ` elements`), elements, fv)
+	}
+	r.validateUnique(list, field, fv, validations)
+	if !set.implicit {
+		validateLength(list.Len(), elements, field.name, fv, validations)
+	}
+}
+
+func (r *reflectRules) validateUnique(list protoreflect.List, field *reflectField, fv *FieldValidator, validations *ValidationErrors) {
+	if !fv.GetUnique() && fv.UniqueBy == nil {
+		return
+	}
+	keyField := field.fd
+	if fv.UniqueBy != nil {
+		if field.fd.Message() == nil {
+			return
+		}
+		keyField = field.fd.Message().Fields().ByName(protoreflect.Name(fv.GetUniqueBy()))
+		if keyField == nil || keyField.Message() != nil || keyField.IsList() || keyField.IsMap() {
+			return
+		}
+	} else if field.fd.Message() != nil {
+		return
+	}
+	errorStr := "be unique, found duplicates at indexes '%d' and '%d'"
+	if fv.UniqueBy != nil {
+		errorStr = fmt.Sprintf("have a unique '%s', found duplicates at indexes '%%d' and '%%d'", fv.GetUniqueBy())
+	}
+	seen := make(map[interface{}]int, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		item := list.Get(i)
+		if fv.UniqueBy != nil {
+			if m := item.Message(); !m.IsValid() || !m.Has(keyField) {
+				continue
+			}
+			item = item.Message().Get(keyField)
+		}
+		var key interface{}
+		switch keyField.Kind() {
+		case protoreflect.BytesKind:
+			key = string(item.Bytes())
+		case protoreflect.EnumKind:
+			key = int32(item.Enum())
+		default:
+			key = item.Interface()
+		}
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = i
+			continue
+		}
+		if fv.GetHumanError() != "" {
+			validations.AddValidationsError(field.name, "unique", i, fv.GetHumanError())
+		} else {
+			validations.AddValidationsError(field.name, "unique", i, fmt.Sprintf("value '%v' must "+errorStr, key, first, i))
+		}
+	}
+}
+
+func validateLength(length int, value interface{}, fieldName string, fv *FieldValidator, validations *ValidationErrors) {
+	if fv.LengthGt != nil && !(int64(length) > fv.GetLengthGt()) {
+		addReflectError(validations, fieldName, "length_gt", fmt.Sprintf(`have a length greater than '%d'`, fv.GetLengthGt()), value, fv)
+	}
+	if fv.LengthLt != nil && !(int64(length) < fv.GetLengthLt()) {
+		addReflectError(validations, fieldName, "length_lt", fmt.Sprintf(`have a length smaller than '%d'`, fv.GetLengthLt()), value, fv)
+	}
+	if fv.LengthEq != nil && !(int64(length) == fv.GetLengthEq()) {
+		addReflectError(validations, fieldName, "length_eq", fmt.Sprintf(`have a length equal to '%d'`, fv.GetLengthEq()), value, fv)
+	}
+}
+
+// validateValue checks the rules of a singular field, or of an element of a repeated field.
+func (r *reflectRules) validateValue(value protoreflect.Value, present bool, field *reflectField, set *reflectRuleSet, fv *FieldValidator, nested bool, violation string, validations *ValidationErrors, options *Options, mask ValidationMask) error {
+	fd := field.fd
+	if fv == nil && fd.Message() == nil {
+		return nil
+	}
+	formatted := formatValue(fd, value)
+	switch {
+	case fd.Kind() == protoreflect.StringKind:
+		s := value.String()
+		if set.regex != nil && !set.regex.MatchString(s) {
+			addReflectError(validations, field.name, "regex", "be a string conforming to regex "+strconv.Quote(set.regex.String()), formatted, fv)
+		}
+		if fv.GetStringNotEmpty() && s == "" {
+			addReflectError(validations, field.name, "string_not_empty", "not be an empty string", formatted, fv)
+		}
+		validateLength(len(s), formatted, field.name, fv, validations)
+	case isIntKind(fd.Kind()):
+		if fv.IntGt != nil && !intGreater(fd, value, fv.GetIntGt()) {
+			addReflectError(validations, field.name, "int_gt", fmt.Sprintf(`be greater than '%d'`, fv.GetIntGt()), formatted, fv)
+		}
+		if fv.IntLt != nil && !intLess(fd, value, fv.GetIntLt()) {
+			addReflectError(validations, field.name, "int_lt", fmt.Sprintf(`be less than '%d'`, fv.GetIntLt()), formatted, fv)
+		}
+	case fd.Kind() == protoreflect.EnumKind:
+		if fv.GetIsInEnum() && fd.Enum().Values().ByNumber(value.Enum()) == nil {
+			typeName := strings.TrimPrefix(string(fd.Enum().FullName()), string(fd.Enum().ParentFile().Package())+".")
+			addReflectError(validations, field.name, "is_in_enum", fmt.Sprintf("be a valid %s field", strings.ReplaceAll(typeName, ".", "_")), formatted, fv)
+		}
+	case isFloatKind(fd.Kind()):
+		validateFloat(fd, value, formatted, field.name, fv, validations)
+	case fd.Kind() == protoreflect.BytesKind:
+		validateLength(len(value.Bytes()), formatted, field.name, fv, validations)
+	case fd.Message() != nil:
+		return r.validateMessageValue(value, present, field, fv, nested, violation, validations, options, mask)
+	}
+	return nil
+}
+
+func (r *reflectRules) validateMessageValue(value protoreflect.Value, present bool, field *reflectField, fv *FieldValidator, nested bool, violation string, validations *ValidationErrors, options *Options, mask ValidationMask) error {
+	fd := field.fd
+	proto2 := fd.ParentFile().Syntax() == protoreflect.Proto2
+	if !proto2 && !present && fv.GetMsgExists() {
+		validations.AddValidationError(field.name, "empty", MessageExistsMessage)
+	}
+	isFieldMask := fd.Message().FullName() == "google.protobuf.FieldMask"
+	if isFieldMask && fv.GetFieldMaskTarget() != "" {
+		if present {
+			if err := checkReflectFieldMask(value.Message(), fd, fv.GetFieldMaskTarget()); err != nil {
+				validations.AddValidationError(field.name, "field_mask_target", err)
+			}
+		}
+		if proto2 {
+			return nil
+		}
+	}
+	if !nested || !present {
+		return nil
+	}
+	if fd.Message().FullName() == "google.protobuf.Any" && withAnyConstraint(fv) {
+		return validateReflectAny(value.Message(), field.name, violation, fv, validations, options)
+	}
+	nestedValidations, err := validateNestedReflect(value.Message(), options, mask)
+	if err != nil {
+		return err
+	}
+	if nestedValidations.IsError() || len(nestedValidations.Warnings) > 0 {
+		validations.AddValidationError(field.name, violation, nestedValidations)
+	}
+	return nil
+}
+
+func validateReflectAny(m protoreflect.Message, fieldName string, violation string, fv *FieldValidator, validations *ValidationErrors, options *Options) error {
+	candidate := reflectAny{m}
+	typeURL := candidate.GetTypeUrl()
+	if len(fv.AnyIn) > 0 {
+		urls := AnyTypeURLs(fv.AnyIn)
+		if !containsString(urls, typeURL) {
+			addReflectError(validations, fieldName, "any_in", fmt.Sprintf(`have a type URL in '%s'`, strings.Join(urls, ", ")), typeURL, fv)
+		}
+	}
+	if len(fv.AnyNotIn) > 0 {
+		urls := AnyTypeURLs(fv.AnyNotIn)
+		if containsString(urls, typeURL) {
+			addReflectError(validations, fieldName, "any_not_in", fmt.Sprintf(`not have a type URL in '%s'`, strings.Join(urls, ", ")), typeURL, fv)
+		}
+	}
+	if !fv.GetAnyValidate() {
+		return nil
+	}
+	msg, err := unpackAny(candidate, options.AnyResolver)
+	if err != nil {
+		validations.AddValidationError(fieldName, violation, err)
+		return nil
+	}
+	if msg == nil {
+		return nil
+	}
+	nestedValidations, err := validateNestedReflect(msg.ProtoReflect(), options, nil)
+	if err != nil {
+		return err
+	}
+	if nestedValidations.IsError() || len(nestedValidations.Warnings) > 0 {
+		validations.AddValidationError(fieldName, violation, nestedValidations)
+	}
+	return nil
+}
+
+// reflectAny reads a google.protobuf.Any through reflection, for dynamic messages.
+type reflectAny struct {
+	m protoreflect.Message
+}
+
+func (a reflectAny) GetTypeUrl() string {
+	return a.m.Get(a.m.Descriptor().Fields().ByName("type_url")).String()
+}
+
+func (a reflectAny) GetValue() []byte {
+	return a.m.Get(a.m.Descriptor().Fields().ByName("value")).Bytes()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func checkReflectFieldMask(mask protoreflect.Message, fd protoreflect.FieldDescriptor, targetName string) error {
+	target := findMessageDescriptor(fd.ParentFile(), protoreflect.FullName(strings.TrimPrefix(targetName, ".")))
+	if target == nil {
+		return fmt.Errorf("unable to resolve the field mask target %s", targetName)
+	}
+	var paths []string
+	list := mask.Get(mask.Descriptor().Fields().ByName("paths")).List()
+	for i := 0; i < list.Len(); i++ {
+		paths = append(paths, list.Get(i).String())
+	}
+	return checkFieldMaskPaths(paths, target, false)
+}
+
+// findMessageDescriptor looks up a message type in a file and its imports, then in the global registry.
+func findMessageDescriptor(file protoreflect.FileDescriptor, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for _, desc := range celFileDescs(file, nil) {
+		if md := findNestedMessage(desc.(protoreflect.FileDescriptor).Messages(), name); md != nil {
+			return md
+		}
+	}
+	if d, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if md, ok := d.(protoreflect.MessageDescriptor); ok {
+			return md
+		}
+	}
+	return nil
+}
+
+func findNestedMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.FullName() == name {
+			return md
+		}
+		if nested := findNestedMessage(md.Messages(), name); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+func validateFloat(fd protoreflect.FieldDescriptor, value protoreflect.Value, formatted interface{}, fieldName string, fv *FieldValidator, validations *ValidationErrors) {
+	if fv == nil {
+		return
+	}
+	upperIsStrict, lowerIsStrict := true, true
+	if fv.FloatLt != nil && fv.FloatLte != nil {
+		if fv.GetFloatLte() < fv.GetFloatLt()+fv.GetFloatEpsilon() {
+			upperIsStrict = false
+		}
+	} else if fv.FloatLte != nil {
+		upperIsStrict = false
+	}
+	if fv.FloatGt != nil && fv.FloatGte != nil {
+		if fv.GetFloatGte() > fv.GetFloatGt()-fv.GetFloatEpsilon() {
+			lowerIsStrict = false
+		}
+	} else if fv.FloatGte != nil {
+		lowerIsStrict = false
+	}
+	x := floatValue(fd, value)
+	float32Kind := fd.Kind() == protoreflect.FloatKind
+	if fv.FloatGt != nil || fv.FloatGte != nil {
+		var ok bool
+		var errorStr string
+		if lowerIsStrict {
+			errorStr = fmt.Sprintf(`be strictly greater than '%g'`, fv.GetFloatGt())
+			if fv.FloatEpsilon != nil {
+				errorStr += fmt.Sprintf(` with a tolerance of '%g'`, fv.GetFloatEpsilon())
+			}
+			if float32Kind {
+				ok = float32(x)+float32(fv.GetFloatEpsilon()) > float32(fv.GetFloatGt())
+			} else {
+				ok = x+fv.GetFloatEpsilon() > fv.GetFloatGt()
+			}
+		} else {
+			errorStr = fmt.Sprintf(`be greater than or equal to '%g'`, fv.GetFloatGte())
+			if float32Kind {
+				ok = float32(x) >= float32(fv.GetFloatGte())
+			} else {
+				ok = x >= fv.GetFloatGte()
+			}
+		}
+		if !ok {
+			addReflectError(validations, fieldName, "float_gt", errorStr, formatted, fv)
+		}
+	}
+	if fv.FloatLt != nil || fv.FloatLte != nil {
+		var ok bool
+		var errorStr string
+		if upperIsStrict {
+			errorStr = fmt.Sprintf(`be strictly lower than '%g'`, fv.GetFloatLt())
+			if fv.FloatEpsilon != nil {
+				errorStr += fmt.Sprintf(` with a tolerance of '%g'`, fv.GetFloatEpsilon())
+			}
+			if float32Kind {
+				ok = float32(x)-float32(fv.GetFloatEpsilon()) < float32(fv.GetFloatLt())
+			} else {
+				ok = x-fv.GetFloatEpsilon() < fv.GetFloatLt()
+			}
+		} else {
+			errorStr = fmt.Sprintf(`be lower than or equal to '%g'`, fv.GetFloatLte())
+			if float32Kind {
+				ok = float32(x) <= float32(fv.GetFloatLte())
+			} else {
+				ok = x <= fv.GetFloatLte()
+			}
+		}
+		if !ok {
+			addReflectError(validations, fieldName, "float_lt", errorStr, formatted, fv)
+		}
+	}
+}
+
+// addReflectError adds a violation the way the generated code does, value being the offending value.
+func addReflectError(validations *ValidationErrors, fieldName string, violation string, specificError string, value interface{}, fv *FieldValidator) {
+	errorMsg := fv.GetHumanError()
+	if errorMsg == "" {
+		errorMsg = fmt.Sprintf("value '%v' must "+specificError, value)
+	}
+	if fv.GetSeverity() == Severity_SEVERITY_WARNING {
+		validations.AddValidationWarning(fieldName, violation, errorMsg)
+		return
+	}
+	validations.AddValidationError(fieldName, violation, errorMsg)
+}
+
+func isIntKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Uint32Kind, protoreflect.Uint64Kind,
+		protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.FloatKind, protoreflect.DoubleKind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+func intGreater(fd protoreflect.FieldDescriptor, value protoreflect.Value, limit int64) bool {
+	if isUnsigned(fd.Kind()) {
+		return limit < 0 || value.Uint() > uint64(limit)
+	}
+	return value.Int() > limit
+}
+
+func intLess(fd protoreflect.FieldDescriptor, value protoreflect.Value, limit int64) bool {
+	if isUnsigned(fd.Kind()) {
+		return limit > 0 && value.Uint() < uint64(limit)
+	}
+	return value.Int() < limit
+}
+
+func floatValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) float64 {
+	switch fd.Kind() {
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return value.Float()
+	case protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return float64(value.Uint())
+	}
+	return float64(value.Int())
+}
+
+// formatValue returns the value of a field as formatted by the generated code, enums being named.
+func formatValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch {
+	case fd.Kind() == protoreflect.EnumKind:
+		if v := fd.Enum().Values().ByNumber(value.Enum()); v != nil {
+			return string(v.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case fd.Message() != nil:
+		return value.Message().Interface()
+	}
+	return value.Interface()
+}
+
+func withAnyConstraint(fv *FieldValidator) bool {
+	return fv != nil && (len(fv.AnyIn) > 0 || len(fv.AnyNotIn) > 0 || fv.AnyValidate != nil)
+}
+
+func skipsNested(md protoreflect.MessageDescriptor) bool {
+	mv, _ := optionsExtension(md.Options(), E_Message).(*MessageValidator)
+	return mv.GetSkipNested()
+}
+
+// optionsExtension reads a validator extension of descriptor options, which are parsed again if the extension was
+// left in their unknown fields.
+func optionsExtension(options proto.Message, xt protoreflect.ExtensionType) interface{} {
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return nil
+	}
+	if !proto.HasExtension(options, xt) && len(options.ProtoReflect().GetUnknown()) > 0 {
+		data, err := proto.Marshal(options)
+		if err != nil {
+			return nil
+		}
+		parsed := options.ProtoReflect().New().Interface()
+		if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, parsed); err != nil {
+			return nil
+		}
+		options = parsed
+	}
+	if !proto.HasExtension(options, xt) {
+		return nil
+	}
+	return proto.GetExtension(options, xt)
+}
+
+// gogoNullable is the field number of the gogoproto.nullable extension, which is not registered with protoregistry.
+const gogoNullable = 65001
+
+// isNullable reports whether gogoproto.nullable is left unset or true, in which case the generated code only validates
+// the proto2 scalar fields which are set.
+func isNullable(fd protoreflect.FieldDescriptor) bool {
+	options, ok := fd.Options().(proto.Message)
+	if !ok || options == nil || !options.ProtoReflect().IsValid() {
+		return true
+	}
+	nullable := true
+	b := options.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return true
+		}
+		b = b[n:]
+		if num == gogoNullable && typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(b)
+			if m < 0 {
+				return true
+			}
+			nullable = v != 0
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return true
+		}
+		b = b[n:]
+	}
+	return nullable
+}
+
+// goName returns the name of the Go field generated for a proto name.
+func goName(name string) string {
+	if name == "" {
+		return ""
+	}
+	t := make([]byte, 0, 32)
+	i := 0
+	if name[0] == '_' {
+		t = append(t, 'X')
+		i++
+	}
+	for ; i < len(name); i++ {
+		c := name[i]
+		if c == '_' && i+1 < len(name) && isASCIILower(name[i+1]) {
+			continue
+		}
+		if '0' <= c && c <= '9' {
+			t = append(t, c)
+			continue
+		}
+		if isASCIILower(c) {
+			c ^= ' '
+		}
+		t = append(t, c)
+		for i+1 < len(name) && isASCIILower(name[i+1]) {
+			i++
+			t = append(t, name[i])
+		}
+	}
+	return string(t)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
@@ -0,0 +1,119 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// tagsFile returns a file declaring a message A with a repeated string field validated by fv.
+func tagsFile(t *testing.T, name string, fv *validator.FieldValidator) protoreflect.FileDescriptor {
+	options := &descriptorpb.FieldOptions{}
+	proto.SetExtension(options, validator.E_Field, fv)
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String(name),
+		Package:    proto.String("reflectrules"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validator.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("A"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("tags"),
+				JsonName: proto.String("tags"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options:  options,
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	return fd
+}
+
+// newTags returns a message of the file built by tagsFile holding tags.
+func newTags(fd protoreflect.FileDescriptor, tags ...string) *dynamicpb.Message {
+	md := fd.Messages().Get(0)
+	m := dynamicpb.NewMessage(md)
+	list := m.Mutable(md.Fields().Get(0)).List()
+	for _, tag := range tags {
+		list.Append(protoreflect.ValueOfString(tag))
+	}
+	return m
+}
+
+func TestValidateReflect_RepeatedElementRules(t *testing.T) {
+	fd := tagsFile(t, "reflect_string_length.proto", &validator.FieldValidator{LengthLt: proto.Int64(10)})
+	tags := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	assert.NoError(t, validator.ValidateReflect(newTags(fd, tags...)), "length_lt should bound each element")
+	err := validator.ValidateReflect(newTags(fd, append(tags, "abcdefghijk")...))
+	assert.EqualError(t, err, "invalid field Tags[10]: value 'abcdefghijk' must have a length smaller than '10'")
+}
+
+func TestValidateReflect_ReloadedDescriptors(t *testing.T) {
+	validate := func(fv *validator.FieldValidator) error {
+		return validator.ValidateReflect(newTags(tagsFile(t, "reflect_reloaded.proto", fv), "a", "b"))
+	}
+	assert.Error(t, validate(&validator.FieldValidator{RepeatedCountMax: proto.Int64(1)}))
+	assert.NoError(t, validate(&validator.FieldValidator{RepeatedCountMax: proto.Int64(2)}), "the rules of a type loaded again are read again")
+}
+
+func TestValidateReflect_Proto3Optional(t *testing.T) {
+	messageOptions := &descriptorpb.MessageOptions{}
+	proto.SetExtension(messageOptions, validator.E_Message, &validator.MessageValidator{
+		Required:     []string{"nickname"},
+		AtLeastOneOf: []*validator.FieldGroup{{Fields: []string{"nickname", "age"}}},
+	})
+	fieldOptions := &descriptorpb.FieldOptions{}
+	proto.SetExtension(fieldOptions, validator.E_Field, &validator.FieldValidator{Regex: proto.String("^[a-z]+$")})
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("reflect_optional_fields.proto"),
+		Package:    proto.String("reflectrules"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validator.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:    proto.String("B"),
+			Options: messageOptions,
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:           proto.String("nickname"),
+				JsonName:       proto.String("nickname"),
+				Number:         proto.Int32(1),
+				Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:           descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				OneofIndex:     proto.Int32(0),
+				Proto3Optional: proto.Bool(true),
+				Options:        fieldOptions,
+			}, {
+				Name:           proto.String("age"),
+				JsonName:       proto.String("age"),
+				Number:         proto.Int32(2),
+				Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:           descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+				OneofIndex:     proto.Int32(1),
+				Proto3Optional: proto.Bool(true),
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_nickname")}, {Name: proto.String("_age")}},
+		}},
+	}, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	md := fd.Messages().Get(0)
+	m := dynamicpb.NewMessage(md)
+	assert.EqualError(t, validator.ValidateReflect(m), "invalid field Nickname: value must be set; invalid field Nickname: one of the fields nickname, age must be set")
+	m.Set(md.Fields().ByName("nickname"), protoreflect.ValueOfString("ABBA"))
+	assert.EqualError(t, validator.ValidateReflect(m), `invalid field Nickname: value 'ABBA' must be a string conforming to regex "^[a-z]+$"`)
+	m.Set(md.Fields().ByName("nickname"), protoreflect.ValueOfString(""))
+	assert.EqualError(t, validator.ValidateReflect(m), `invalid field Nickname: value '' must be a string conforming to regex "^[a-z]+$"`, "an empty value is set")
+	m.Set(md.Fields().ByName("nickname"), protoreflect.ValueOfString("abba"))
+	assert.NoError(t, validator.ValidateReflect(m))
+}
@@ -0,0 +1,145 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// RegisteredValidator is the validator of a message type, registered by the generated code.
+type RegisteredValidator struct {
+	// FullName is the full name of the message type, such as "acme.users.v1.User".
+	FullName protoreflect.FullName
+	// New returns an empty message of the type.
+	New func() interface{}
+}
+
+// Validate validates all fields of msg, which must be a message of the registered type.
+func (v *RegisteredValidator) Validate(msg interface{}) error {
+	if reflect.TypeOf(msg) != reflect.TypeOf(v.New()) {
+		return fmt.Errorf("unable to validate %T with the validator of %s", msg, v.FullName)
+	}
+	return CallValidatorsIfExists(msg)
+}
+
+// Unmarshal returns the message of the registered type encoded in data.
+func (v *RegisteredValidator) Unmarshal(data []byte) (interface{}, error) {
+	msg := v.New()
+	m, ok := msg.(protoadapt.MessageV1)
+	if !ok {
+		return nil, fmt.Errorf("unable to unmarshal %T", msg)
+	}
+	if err := proto.Unmarshal(data, protoadapt.MessageV2Of(m)); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal type '%s': %v", v.FullName, err)
+	}
+	return msg, nil
+}
+
+var (
+	registryMu sync.RWMutex
+	// registry holds the validators of each full name, the golang flavour of a type first.
+	registry = map[protoreflect.FullName][]*RegisteredValidator{}
+	// registryTypes holds the validators of each Go type.
+	registryTypes = map[reflect.Type]*RegisteredValidator{}
+)
+
+// RegisterValidator is used by the generated code to register the validator of a message type. The golang and gogo
+// flavours of a type share its full name: both are registered and told apart by their Go types. A Go type registered
+// again keeps the last registration.
+func RegisterValidator(fullName protoreflect.FullName, newMessage func() interface{}) {
+	v := &RegisteredValidator{FullName: fullName, New: newMessage}
+	goType := reflect.TypeOf(newMessage())
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if registered, ok := registryTypes[goType]; ok && registered.FullName != fullName {
+		panic(fmt.Sprintf("validator: %v registered as both %s and %s", goType, registered.FullName, fullName))
+	}
+	registryTypes[goType] = v
+	validators := registry[fullName]
+	for i, registered := range validators {
+		if reflect.TypeOf(registered.New()) == goType {
+			validators[i] = v
+			return
+		}
+	}
+	validators = append(validators, v)
+	sort.SliceStable(validators, func(i, j int) bool {
+		return isGolangMessage(validators[i]) && !isGolangMessage(validators[j])
+	})
+	registry[fullName] = validators
+}
+
+// isGolangMessage reports whether v validates messages generated by protoc-gen-go, as opposed to gogo messages.
+func isGolangMessage(v *RegisteredValidator) bool {
+	_, ok := v.New().(proto.Message)
+	return ok
+}
+
+// LookupValidator returns the validator registered for the message type with the given full name, the one of its
+// golang flavour if its gogo flavour is registered as well.
+func LookupValidator(fullName protoreflect.FullName) (*RegisteredValidator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	validators := registry[fullName]
+	if len(validators) == 0 {
+		return nil, false
+	}
+	return validators[0], true
+}
+
+// LookupValidatorFor returns the validator registered for the Go type of msg.
+func LookupValidatorFor(msg interface{}) (*RegisteredValidator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	v, ok := registryTypes[reflect.TypeOf(msg)]
+	return v, ok
+}
+
+// RangeValidators calls f for each registered validator, in the order of their full names, until f returns false.
+// The golang flavour of a type comes before its gogo flavour.
+func RangeValidators(f func(*RegisteredValidator) bool) {
+	registryMu.RLock()
+	var validators []*RegisteredValidator
+	for _, registered := range registry {
+		validators = append(validators, registered...)
+	}
+	registryMu.RUnlock()
+	sort.SliceStable(validators, func(i, j int) bool {
+		return validators[i].FullName < validators[j].FullName
+	})
+	for _, v := range validators {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// ValidateAny unpacks the message held by a and validates all of its fields with the registered validator of its
+// type. It fails if no validator is registered for the type.
+func ValidateAny(a *anypb.Any) error {
+	if a == nil {
+		return nil
+	}
+	fullName := a.MessageName()
+	if !fullName.IsValid() {
+		return fmt.Errorf("invalid type URL '%s'", a.GetTypeUrl())
+	}
+	v, ok := LookupValidator(fullName)
+	if !ok {
+		return fmt.Errorf("no validator registered for type '%s'", fullName)
+	}
+	msg, err := v.Unmarshal(a.GetValue())
+	if err != nil {
+		return err
+	}
+	return v.Validate(msg)
+}
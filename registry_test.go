@@ -0,0 +1,49 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// gogoStringValue stands for the gogo flavour of google.protobuf.StringValue.
+type gogoStringValue struct {
+	Value string
+}
+
+func (m *gogoStringValue) Reset()         { *m = gogoStringValue{} }
+func (m *gogoStringValue) String() string { return m.Value }
+func (*gogoStringValue) ProtoMessage()    {}
+
+func TestRegistry_BothFlavours(t *testing.T) {
+	const fullName = "validatortest.registry.StringValue"
+	validator.RegisterValidator(fullName, func() interface{} { return &gogoStringValue{} })
+	validator.RegisterValidator(fullName, func() interface{} { return &wrapperspb.StringValue{} })
+
+	v, ok := validator.LookupValidator(fullName)
+	assert.True(t, ok)
+	assert.IsType(t, &wrapperspb.StringValue{}, v.New(), "the golang flavour is looked up by full name")
+	v, ok = validator.LookupValidatorFor(&gogoStringValue{})
+	assert.True(t, ok)
+	assert.IsType(t, &gogoStringValue{}, v.New(), "the gogo flavour is still registered")
+	assert.NoError(t, v.Validate(&gogoStringValue{}))
+
+	var registered []interface{}
+	validator.RangeValidators(func(v *validator.RegisteredValidator) bool {
+		if v.FullName == fullName {
+			registered = append(registered, v.New())
+		}
+		return true
+	})
+	assert.Equal(t, []interface{}{&wrapperspb.StringValue{}, &gogoStringValue{}}, registered)
+
+	assert.Panics(t, func() {
+		validator.RegisterValidator("validatortest.registry.Other", func() interface{} { return &gogoStringValue{} })
+	}, "a Go type has a single full name")
+}
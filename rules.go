@@ -0,0 +1,168 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Messages of the violations which are not about the value of a field, shared by the generated code and
+// ValidateReflect.
+const (
+	// OneofRequiredMessage is the message of the violations of validator.oneof required.
+	OneofRequiredMessage = "one of the fields must be set"
+	// MessageExistsMessage is the message of the violations of msg_exists.
+	MessageExistsMessage = "message must exist"
+)
+
+const uuidPattern = "^([a-fA-F0-9]{8}-" +
+	"[a-fA-F0-9]{4}-" +
+	"[%s][a-fA-F0-9]{3}-" +
+	"[8|9|aA|bB][a-fA-F0-9]{3}-" +
+	"[a-fA-F0-9]{12})?$"
+
+// Rules are the validator options of a message type, for tools mirroring the server-side rules such as form builders.
+type Rules struct {
+	// FullName is the full name of the message type.
+	FullName protoreflect.FullName
+	// Message holds the rules set with validator.message, nil if there are none.
+	Message *MessageValidator
+	// Fields holds the rules set with validator.field, by field name as declared in the proto file.
+	Fields map[string]*FieldValidator
+	// Oneofs holds the rules set with validator.oneof, by oneof name as declared in the proto file.
+	Oneofs map[string]*OneofValidator
+}
+
+// MessageRules returns the rules of the type of msg, a golang or gogo message, nil for other values.
+func MessageRules(msg interface{}) *Rules {
+	md := reflectDescriptor(reflect.ValueOf(msg))
+	if md == nil {
+		return nil
+	}
+	return DescriptorRules(md)
+}
+
+// DescriptorRules returns the rules of a message type read from its descriptor. The returned options are copies which
+// can be modified freely.
+func DescriptorRules(md protoreflect.MessageDescriptor) *Rules {
+	rules := &Rules{
+		FullName: md.FullName(),
+		Fields:   map[string]*FieldValidator{},
+		Oneofs:   map[string]*OneofValidator{},
+	}
+	if mv, ok := optionsExtension(md.Options(), E_Message).(*MessageValidator); ok && mv != nil {
+		rules.Message = proto.Clone(mv).(*MessageValidator)
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if fv, ok := optionsExtension(fd.Options(), E_Field).(*FieldValidator); ok && fv != nil {
+			rules.Fields[string(fd.Name())] = proto.Clone(fv).(*FieldValidator)
+		}
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if ov, ok := optionsExtension(od.Options(), E_Oneof).(*OneofValidator); ok && ov != nil {
+			rules.Oneofs[string(od.Name())] = proto.Clone(ov).(*OneofValidator)
+		}
+	}
+	return rules
+}
+
+// ItemRules returns the rules applied to each element of a repeated field of the given kind, those of validator.items.
+// Only collection rules and rules which are not checked against the values can be set on the field itself, length_*
+// included except for string and bytes fields without validator.items, where they would be mistaken for bounds of
+// each element.
+func ItemRules(kind protoreflect.Kind, fv *FieldValidator) (*FieldValidator, error) {
+	if fv == nil {
+		return nil, nil
+	}
+	elementRules := proto.Clone(fv).(*FieldValidator)
+	elementRules.RepeatedCountMin, elementRules.RepeatedCountMax = nil, nil
+	elementRules.Unique, elementRules.UniqueBy, elementRules.Items = nil, nil, nil
+	elementRules.HumanError, elementRules.MsgExists, elementRules.Severity = nil, nil, nil
+	elementRules.Groups, elementRules.GroupRules, elementRules.Cel = nil, nil, nil
+	elementRules.RequiredIf, elementRules.RequiredUnless, elementRules.ForbiddenIf = nil, nil, nil
+	elementRules.Immutable, elementRules.Monotonic, elementRules.Transitions = nil, nil, nil
+	if fv.Items == nil && (kind == protoreflect.StringKind || kind == protoreflect.BytesKind) {
+		if lengths := setRuleNames(&FieldValidator{LengthGt: fv.LengthGt, LengthLt: fv.LengthLt, LengthEq: fv.LengthEq}); len(lengths) > 0 {
+			return nil, fmt.Errorf("%s of a repeated %v field is ambiguous, set it in validator.items to bound each element or use repeated_count_* to bound the number of elements", strings.Join(lengths, ", "), kind)
+		}
+	}
+	elementRules.LengthGt, elementRules.LengthLt, elementRules.LengthEq = nil, nil, nil
+	if names := setRuleNames(elementRules); len(names) > 0 {
+		return nil, fmt.Errorf("%s of a repeated field must be set in validator.items", strings.Join(names, ", "))
+	}
+	return fv.Items, nil
+}
+
+// ImplicitItemRules reports whether the rules of a repeated field of the given kind are applied to each of its
+// elements, as done by the versions predating validator.items: the field has no validator.items and sets rules which
+// ItemRules rejects, length_* bounding each string or bytes element. This is deprecated, element rules should be moved
+// to validator.items.
+func ImplicitItemRules(kind protoreflect.Kind, fv *FieldValidator) bool {
+	if fv == nil || fv.Items != nil {
+		return false
+	}
+	_, err := ItemRules(kind, fv)
+	return err != nil
+}
+
+// setRuleNames returns the sorted names of the rules set in fv.
+func setRuleNames(fv *FieldValidator) []string {
+	var names []string
+	fv.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		names = append(names, string(fd.Name()))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// ValueRules returns the rules of a field which are checked along with its value, dropping the CEL expressions, the
+// conditions on other fields and the transition rules. It returns nil if there is nothing left.
+func ValueRules(fv *FieldValidator) *FieldValidator {
+	if fv == nil {
+		return nil
+	}
+	valueRules := proto.Clone(fv).(*FieldValidator)
+	valueRules.Cel = nil
+	valueRules.RequiredIf, valueRules.RequiredUnless, valueRules.ForbiddenIf = nil, nil, nil
+	valueRules.Immutable, valueRules.Monotonic, valueRules.Transitions = nil, nil, nil
+	if proto.Equal(valueRules, &FieldValidator{}) {
+		return nil
+	}
+	return valueRules
+}
+
+// UUIDRegex returns the regex of uuid_ver, matching the UUIDs of a version between 1 and 5, or of any of them for 0.
+func UUIDRegex(version int32) (string, error) {
+	switch {
+	case version < 0 || version > 5:
+		return "", fmt.Errorf("UUID version should be between 0-5, Got %d", version)
+	case version == 0:
+		return fmt.Sprintf(uuidPattern, "1-5"), nil
+	default:
+		return fmt.Sprintf(uuidPattern, strconv.Itoa(int(version))), nil
+	}
+}
+
+// AnyTypeURLs expands the entries of any_in and any_not_in into full type URLs, prefixing the type names with
+// "type.googleapis.com/".
+func AnyTypeURLs(entries []string) []string {
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			entry = "type.googleapis.com/" + strings.TrimPrefix(entry, ".")
+		}
+		urls = append(urls, entry)
+	}
+	return urls
+}
@@ -0,0 +1,134 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator
+
+import (
+	"reflect"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ShadowPolicy turns the rules of message types, packages or named rules into observe-only mode, for instance while
+// rolling out a new rule. Their violations are recorded instead of being returned.
+type ShadowPolicy struct {
+	mu       sync.RWMutex
+	messages map[string]bool
+	packages map[string]bool
+	rules    map[string]bool
+	record   func(message string, violation *ValidationError)
+}
+
+// NewShadowPolicy returns a policy passing the shadowed violations to record, along with the full name of the
+// message type they were found in.
+func NewShadowPolicy(record func(message string, violation *ValidationError)) *ShadowPolicy {
+	return &ShadowPolicy{
+		messages: map[string]bool{},
+		packages: map[string]bool{},
+		rules:    map[string]bool{},
+		record:   record,
+	}
+}
+
+// ShadowMessage sets whether the rules of the message type with the given full name are shadowed.
+func (p *ShadowPolicy) ShadowMessage(fullName string, shadowed bool) {
+	p.set(p.messages, fullName, shadowed)
+}
+
+// ShadowPackage sets whether the rules of the message types of a proto package are shadowed.
+func (p *ShadowPolicy) ShadowPackage(pkg string, shadowed bool) {
+	p.set(p.packages, pkg, shadowed)
+}
+
+// ShadowRule sets whether a rule is shadowed, rules being named after the violations they report, such as "regex"
+// or the id of a cel expression. The violations of nested messages, such as "message", are not rules: the violations
+// found in the nested messages are shadowed by their own rules.
+func (p *ShadowPolicy) ShadowRule(violation string, shadowed bool) {
+	p.set(p.rules, violation, shadowed)
+}
+
+func (p *ShadowPolicy) set(names map[string]bool, name string, shadowed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if shadowed {
+		names[name] = true
+	} else {
+		delete(names, name)
+	}
+}
+
+// CallValidatorsIfExists validates all fields of candidate like CallValidatorsIfExists, recording the shadowed
+// violations instead of returning them.
+func (p *ShadowPolicy) CallValidatorsIfExists(candidate interface{}) error {
+	err := CallValidatorsIfExists(candidate)
+	validations, ok := err.(*ValidationErrors)
+	if p == nil || !ok {
+		return err
+	}
+	return p.shadow(reflect.ValueOf(candidate), validations).Err()
+}
+
+// shadow records the shadowed violations of the message v and returns the others.
+func (p *ShadowPolicy) shadow(v reflect.Value, validations *ValidationErrors) *ValidationErrors {
+	md := reflectDescriptor(v)
+	var name string
+	if md != nil {
+		name = string(md.FullName())
+	}
+	p.mu.RLock()
+	shadowed := md != nil && (p.messages[name] || p.packages[string(md.ParentFile().Package())])
+	p.mu.RUnlock()
+	kept := &ValidationErrors{Warnings: append([]*ValidationError(nil), validations.Warnings...)}
+	for _, err := range validations.Errors {
+		p.mu.RLock()
+		rule := err.Errors == nil && p.rules[err.Violation]
+		p.mu.RUnlock()
+		if shadowed || rule {
+			if p.record != nil {
+				p.record(name, err)
+			}
+			continue
+		}
+		if err.Errors != nil {
+			_, value, _ := maskField(v, err.Field)
+			if value.Kind() == reflect.Slice && err.Index < value.Len() {
+				value = value.Index(err.Index)
+			}
+			nested := *err
+			nested.Errors = p.shadow(value, err.Errors)
+			if !nested.Errors.IsError() {
+				// a nested message only reporting warnings is valid
+				if len(nested.Errors.Warnings) > 0 {
+					kept.Warnings = append(kept.Warnings, &nested)
+				}
+				continue
+			}
+			err = &nested
+		}
+		kept.Errors = append(kept.Errors, err)
+	}
+	return kept
+}
+
+func reflectDescriptor(v reflect.Value) protoreflect.MessageDescriptor {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Struct && v.CanAddr() {
+		// non-nullable gogo message fields
+		v = v.Addr()
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	switch m := v.Interface().(type) {
+	case proto.Message:
+		return m.ProtoReflect().Descriptor()
+	case protoadapt.MessageV1:
+		return protoadapt.MessageV2Of(m).ProtoReflect().Descriptor()
+	}
+	return nil
+}
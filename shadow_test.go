@@ -0,0 +1,62 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	validator "github.com/monstrum/go-proto-validators"
+)
+
+// fixedValidators reports the same violations whenever it is validated.
+type fixedValidators struct {
+	validations *validator.ValidationErrors
+}
+
+func (f fixedValidators) ValidateAll() error {
+	return f.validations.Err()
+}
+
+func TestShadowPolicy_NestedViolations(t *testing.T) {
+	var recorded []string
+	policy := validator.NewShadowPolicy(func(message string, violation *validator.ValidationError) {
+		recorded = append(recorded, violation.Field+":"+violation.Violation)
+	})
+	nested := &validator.ValidationErrors{}
+	nested.AddValidationError("Zip", "regex", "value must match")
+	nested.AddValidationWarning("Street", "length_lt", "value is long")
+	candidate := fixedValidators{&validator.ValidationErrors{}}
+	candidate.validations.AddValidationsError("Addresses", "array", 1, nested)
+
+	policy.ShadowRule("array", true)
+	err := policy.CallValidatorsIfExists(candidate)
+	assert.Error(t, err, "the violations of nested messages are not shadowed by their nesting")
+	assert.Empty(t, recorded)
+
+	policy.ShadowRule("array", false)
+	policy.ShadowRule("regex", true)
+	assert.NoError(t, policy.CallValidatorsIfExists(candidate))
+	assert.Equal(t, []string{"Zip:regex"}, recorded)
+}
+
+func TestShadowPolicy_KeepsNestedWarnings(t *testing.T) {
+	policy := validator.NewShadowPolicy(nil)
+	policy.ShadowRule("regex", true)
+	nested := &validator.ValidationErrors{}
+	nested.AddValidationError("Zip", "regex", "value must match")
+	nested.AddValidationWarning("Street", "length_lt", "value is long")
+	candidate := &validator.ValidationErrors{}
+	candidate.AddValidationError("Name", "string_not_empty", "value must not be empty")
+	candidate.AddValidationError("Address", "message", nested)
+
+	err := policy.CallValidatorsIfExists(fixedValidators{candidate})
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	if assert.Len(t, validations.Warnings, 1, "the warnings of a nested message left valid are kept") {
+		assert.Equal(t, "Address", validations.Warnings[0].Field)
+		assert.Equal(t, "Street", validations.Warnings[0].Errors.Warnings[0].Field)
+	}
+}
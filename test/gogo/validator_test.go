@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	validator "github.com/monstrum/go-proto-validators"
 )
 
 var (
@@ -173,6 +176,18 @@ func TestGoodProto2(t *testing.T) {
 	}
 }
 
+func TestOptionsAlongGogoOptions(t *testing.T) {
+	someProto2 := buildProto2("-%ab", 11, "abba", 99, 0.5, 0.5, 0.5, 0.5, "x", 4, "1234567890", stableBytes, uuid1, uuid4, 0, 0)
+	someProto2.IntReqNonNull = 0
+	err := someProto2.Validate()
+	if assert.Error(t, err, "rules of fields with gogoproto options must be generated") {
+		assert.Contains(t, err.Error(), "IntReqNonNull")
+	}
+	someProto2.IntReqNonNull = 11
+	someProto2.StringReqNonNull = "toolong"
+	assert.Error(t, someProto2.Validate())
+}
+
 func TestStringRegex(t *testing.T) {
 	tooLong1Proto3 := buildProto3("toolong", 11, "abba", 99, 0.5, 0.5, 0.5, 0.5, "x", 4, "1234567890", stableBytes, uuid1, uuid4, 0, 0)
 	if tooLong1Proto3.Validate() == nil {
@@ -613,3 +628,21 @@ func TestUUID4Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationRules(t *testing.T) {
+	rules := (&OneOfMessage3{}).ValidationRules()
+	assert.Equal(t, "validatortest.OneOfMessage3", string(rules.FullName))
+	assert.Equal(t, int64(10), rules.Fields["SomeInt"].GetIntGt())
+	assert.True(t, rules.Oneofs["something"].GetRequired())
+}
+
+func TestMask_CustomName(t *testing.T) {
+	example := &MaskUser3{Id: "u1", DisplayName: "Alice", Email: "alice@example.com", Username: "Not a login"}
+	assert.NoError(t, example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"id"}}))
+	err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"login"}})
+	assert.Error(t, err, "fields with a custom name are listed by their proto name")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "Username", validations.Errors[0].Field)
+	assert.EqualError(t, err, example.ValidateAll().Error())
+}
@@ -4,11 +4,40 @@
 package validatortest
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	fmt "fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+	plugin_go "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	validator "github.com/monstrum/go-proto-validators"
+	validatorplugin "github.com/monstrum/go-proto-validators/plugin"
 )
 
 var (
@@ -173,6 +202,149 @@ func TestGoodProto2(t *testing.T) {
 	}
 }
 
+func TestGenerate_Proto2RequiredMessage(t *testing.T) {
+	// required message fields without rules used to make the generator dereference a nil validator
+	code, err := generateGo(t, validatorplugin.Options{}, File_validator_proto2_proto)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "if this.EmbeddedReq != nil {")
+
+	goodProto2 := buildProto2("-%ab", 11, "abba", 99, 0.5, 0.5, 0.5, 0.5, "x", 4, "1234567890", stableBytes, uuid1, uuid4, 1, 1)
+	goodProto2.EmbeddedReq = nil
+	assert.NoError(t, goodProto2.Validate())
+	assert.NoError(t, goodProto2.ValidateAll())
+
+	goodProto2.EmbeddedReq = &ValidatorMessage_EmbeddedMessage{Identifier: proto.String("ab"), SomeValue: proto.Int64(101)}
+	assert.EqualError(t, goodProto2.Validate(), "invalid field EmbeddedReq.SomeValue: value '101' must be less than '100'", "required message fields are validated")
+	assert.Equal(t, goodProto2.ValidateAll(), validator.ValidateReflect(goodProto2.ProtoReflect()))
+}
+
+func TestGenerate_OptionsAlongGogoOptions(t *testing.T) {
+	// validator options used to be dropped, gogo failing to decode the extensions generated with protoc-gen-go
+	code, err := generateGo(t, validatorplugin.Options{}, File_validator_proto2_proto)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "var _regex_ValidatorMessage_StringReqNonNull = regexp.MustCompile(`^.{2,5}$`)")
+	assert.Contains(t, code, "if !(this.GetIntReqNonNull() > 0) {")
+}
+
+// repeatedRulesFile returns a file declaring a message A with a repeated field of the given type validated by fv.
+func repeatedRulesFile(t *testing.T, name string, typ descriptorpb.FieldDescriptorProto_Type, typeName string, fv *validator.FieldValidator) protoreflect.FileDescriptor {
+	options := &descriptorpb.FieldOptions{}
+	proto.SetExtension(options, validator.E_Field, fv)
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(name),
+		Package:    proto.String("repeatedrules"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validator.proto", "google/protobuf/any.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/repeatedrules")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("A"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("tags"),
+				JsonName: proto.String("tags"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     typ.Enum(),
+				TypeName: proto.String(typeName),
+				Options:  options,
+			}},
+		}},
+	}
+	if typeName == "" {
+		fdp.MessageType[0].Field[0].TypeName = nil
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	return fd
+}
+
+func TestGenerate_RepeatedElementRules(t *testing.T) {
+	// without validator.items, the rules are applied to each element as they used to be
+	stringLength := repeatedRulesFile(t, "string_length.proto", descriptorpb.FieldDescriptorProto_TYPE_STRING, "", &validator.FieldValidator{LengthLt: proto.Int64(10)})
+	code, err := generateGo(t, validatorplugin.Options{}, stringLength)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "if !(len(item) < 10) {")
+	assert.Contains(t, code, `validations.AddValidationsError("Tags", "length_lt", i, `)
+	assert.NotContains(t, code, "len(this.Tags)")
+	_, err = generateGo(t, validatorplugin.Options{ExplicitItemRules: true}, stringLength)
+	assert.ErrorContains(t, err, "field A.Tags: length_lt of a repeated string field is ambiguous, set it in validator.items to bound each element or use repeated_count_* to bound the number of elements")
+	_, err = validatorplugin.GenerateJSONSchema(generatorRequest(t, "", stringLength), validatorplugin.Options{ExplicitItemRules: true})
+	assert.EqualError(t, err, "field repeatedrules.A.tags: length_lt of a repeated string field is ambiguous, set it in validator.items to bound each element or use repeated_count_* to bound the number of elements")
+	_, err = validatorplugin.GenerateJSONSchema(generatorRequest(t, "", stringLength), validatorplugin.Options{})
+	assert.NoError(t, err)
+
+	anyIn := repeatedRulesFile(t, "any_in.proto", descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any", &validator.FieldValidator{AnyIn: []string{"repeatedrules.A"}})
+	_, err = generateGo(t, validatorplugin.Options{ExplicitItemRules: true}, anyIn)
+	assert.ErrorContains(t, err, "field A.Tags: any_in of a repeated field must be set in validator.items")
+	mixed := repeatedRulesFile(t, "mixed.proto", descriptorpb.FieldDescriptorProto_TYPE_STRING, "", &validator.FieldValidator{Regex: proto.String("^[a-z]+$"), Items: &validator.FieldValidator{LengthGt: proto.Int64(0)}})
+	_, err = generateGo(t, validatorplugin.Options{}, mixed)
+	assert.ErrorContains(t, err, "field A.Tags: regex of a repeated field must be set in validator.items", "element rules cannot be set along validator.items")
+
+	// length_* of other fields and along validator.items bound the number of elements
+	intLength := repeatedRulesFile(t, "int_length.proto", descriptorpb.FieldDescriptorProto_TYPE_INT32, "", &validator.FieldValidator{LengthLt: proto.Int64(10)})
+	code, err = generateGo(t, validatorplugin.Options{}, intLength)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "if !(len(this.Tags) < 10) {")
+	withItems := repeatedRulesFile(t, "with_items.proto", descriptorpb.FieldDescriptorProto_TYPE_STRING, "", &validator.FieldValidator{LengthLt: proto.Int64(10), Items: &validator.FieldValidator{LengthGt: proto.Int64(0)}})
+	code, err = generateGo(t, validatorplugin.Options{}, withItems)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "if !(len(this.Tags) < 10) {")
+	assert.Contains(t, code, "if !(len(item) > 0) {")
+}
+
+// optionalFieldsFile returns a proto3 file declaring a message B requiring its optional fields.
+func optionalFieldsFile(t *testing.T) protoreflect.FileDescriptor {
+	messageOptions := &descriptorpb.MessageOptions{}
+	proto.SetExtension(messageOptions, validator.E_Message, &validator.MessageValidator{
+		Required:     []string{"nickname"},
+		AtLeastOneOf: []*validator.FieldGroup{{Fields: []string{"nickname", "age"}}},
+	})
+	fieldOptions := &descriptorpb.FieldOptions{}
+	proto.SetExtension(fieldOptions, validator.E_Field, &validator.FieldValidator{Regex: proto.String("^[a-z]+$")})
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("optional_fields.proto"),
+		Package:    proto.String("optionalfields"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validator.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/optionalfields")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:    proto.String("B"),
+			Options: messageOptions,
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:           proto.String("nickname"),
+				JsonName:       proto.String("nickname"),
+				Number:         proto.Int32(1),
+				Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:           descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				OneofIndex:     proto.Int32(0),
+				Proto3Optional: proto.Bool(true),
+				Options:        fieldOptions,
+			}, {
+				Name:           proto.String("age"),
+				JsonName:       proto.String("age"),
+				Number:         proto.Int32(2),
+				Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:           descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+				OneofIndex:     proto.Int32(1),
+				Proto3Optional: proto.Bool(true),
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_nickname")}, {Name: proto.String("_age")}},
+		}},
+	}, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	return fd
+}
+
+func TestGenerate_Proto3Optional(t *testing.T) {
+	// the synthetic oneofs of proto3 optional fields used to make required and field groups fail
+	code, err := generateGo(t, validatorplugin.Options{}, optionalFieldsFile(t))
+	assert.NoError(t, err)
+	assert.Contains(t, code, "if !(this.Nickname != nil) {")
+	assert.Contains(t, code, "if !(this.Nickname != nil || this.Age != nil) {")
+	assert.Contains(t, code, "if this.Nickname != nil {")
+	assert.Contains(t, code, "_regex_B_Nickname.MatchString(*(this.Nickname))")
+	assert.NotContains(t, code, "GetXNickname")
+}
+
 func TestStringRegex(t *testing.T) {
 	tooLong1Proto3 := buildProto3("toolong", 11, "abba", 99, 0.5, 0.5, 0.5, 0.5, "x", 4, "1234567890", stableBytes, uuid1, uuid4, 0, 0)
 	if tooLong1Proto3.Validate() == nil {
@@ -484,6 +656,8 @@ func TestNestedError3(t *testing.T) {
 		t.Fatalf("expected fail due to nested SomeEmbeddedNonNullable.SomeValue being wrong")
 	} else if !strings.HasPrefix(err.Error(), "invalid field SomeEmbeddedNonNullable.SomeValue:") {
 		t.Fatalf("expected fieldError, got '%v'", err)
+	} else {
+		assert.EqualError(t, err, "invalid field SomeEmbeddedNonNullable.SomeValue: value '101' must be less than '100'")
 	}
 }
 
@@ -513,6 +687,16 @@ func TestOneOf_Required(t *testing.T) {
 	err := example.Validate()
 	assert.Error(t, err, "oneof.required should fail if none of the oneof fields are set")
 	assert.Contains(t, err.Error(), "Something", "error must err on the Something field")
+
+	// the error used to be formatted with an undefined Something variable
+	validations := example.ValidateAllWithWarnings()
+	if assert.Len(t, validations.Errors, 1) {
+		assert.Equal(t, "Something", validations.Errors[0].Field)
+		assert.Equal(t, "one_of", validations.Errors[0].Violation)
+	}
+	code, err := generateGo(t, validatorplugin.Options{}, File_validator_proto3_oneof_proto)
+	assert.NoError(t, err)
+	assert.NotContains(t, code, ", Something)")
 }
 
 func TestOneOf_NestedMessage(t *testing.T) {
@@ -589,6 +773,13 @@ func TestOneOf_Regex(t *testing.T) {
 	assert.NoError(t, err, "This message should pass all validation")
 }
 
+func TestOneOf_RequiredMessage(t *testing.T) {
+	example := &OneOfMessage3{SomeInt: 30}
+	assert.EqualError(t, example.Validate(), "invalid field Something: one of the fields must be set")
+	assert.Equal(t, []string{"Something[0] one_of: one of the fields must be set"}, violations(example.ValidateAll()))
+	assert.Equal(t, example.ValidateAll(), validator.ValidateReflect(example.ProtoReflect()))
+}
+
 func TestUUID4Validation(t *testing.T) {
 	testcases := []struct {
 		uuid string
@@ -638,3 +829,1469 @@ func TestUUID4Validation(t *testing.T) {
 		})
 	}
 }
+
+func mustPackAny(t *testing.T, msg proto.Message) *anypb.Any {
+	packed, err := anypb.New(msg)
+	if err != nil {
+		t.Fatalf("unexpected error packing Any: %v", err)
+	}
+	return packed
+}
+
+func TestAny_TypeURLRules(t *testing.T) {
+	goodPayload := &AnyPayload{Identifier: "abba"}
+	example := &AnyMessage3{
+		Restricted: mustPackAny(t, goodPayload),
+		Denied:     mustPackAny(t, goodPayload),
+	}
+	assert.NoError(t, example.Validate(), "allowed type URLs should pass validation")
+
+	example.Restricted = mustPackAny(t, &OneOfMessage3{})
+	err := example.Validate()
+	assert.Error(t, err, "any_in should reject type URLs outside of the list")
+	assert.Contains(t, err.Error(), "Restricted")
+
+	example.Restricted = mustPackAny(t, &ExternalMsg{})
+	example.Denied = mustPackAny(t, &ExternalMsg{})
+	err = example.Validate()
+	assert.Error(t, err, "any_not_in should reject type URLs in the list")
+	assert.Contains(t, err.Error(), "Denied")
+}
+
+func TestAny_RecursiveValidation(t *testing.T) {
+	example := &AnyMessage3{
+		Validated: mustPackAny(t, &AnyPayload{Identifier: "abba"}),
+	}
+	assert.NoError(t, example.Validate(), "valid packed message should pass validation")
+
+	example.Validated = mustPackAny(t, &AnyPayload{Identifier: "999"})
+	err := example.Validate()
+	assert.Error(t, err, "invalid packed message should fail validation")
+	assert.Contains(t, err.Error(), "Validated.Identifier")
+
+	err = example.ValidateAll()
+	assert.Error(t, err)
+	validations, ok := err.(*validator.ValidationErrors)
+	assert.True(t, ok, "ValidateAll must return ValidationErrors")
+	assert.Equal(t, "Validated", validations.Errors[0].Field)
+	assert.Equal(t, "Identifier", validations.Errors[0].Errors.Errors[0].Field)
+
+	example.Validated = &anypb.Any{TypeUrl: "type.googleapis.com/validatortest.Unknown"}
+	assert.Error(t, example.Validate(), "unresolvable packed types should fail validation")
+}
+
+func TestAny_ContextResolver(t *testing.T) {
+	example := &AnyMessage3{
+		Validated: mustPackAny(t, &AnyPayload{Identifier: "999"}),
+	}
+	err := validator.ValidateContext(context.Background(), example)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Validated.Identifier")
+
+	types := &protoregistry.Types{}
+	err = validator.ValidateContext(context.Background(), example, validator.ResolveAny(types))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to resolve type", "the resolver of the options should be used")
+	err = validator.ValidateReflect(example.ProtoReflect(), validator.ResolveAny(types))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to resolve type")
+
+	assert.NoError(t, types.RegisterMessage((&AnyPayload{}).ProtoReflect().Type()))
+	err = validator.ValidateContext(context.Background(), example, validator.ResolveAny(types))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Validated.Identifier")
+}
+
+func TestAny_RepeatedItems(t *testing.T) {
+	example := &AnyMessage3{
+		ValidatedRep: []*anypb.Any{
+			mustPackAny(t, &AnyPayload{Identifier: "abba"}),
+			mustPackAny(t, &AnyPayload{Identifier: "999"}),
+		},
+	}
+	err := example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "ValidatedRep", validations.Errors[0].Field)
+	assert.Equal(t, 1, validations.Errors[0].Index)
+}
+
+func TestUnique_Scalars(t *testing.T) {
+	example := &UniqueMessage3{
+		Tags:   []string{"a", "b", "c"},
+		Scopes: []Scope{Scope_SCOPE_READ, Scope_SCOPE_WRITE},
+		Ids:    []int64{1, 2, 3},
+		Blobs:  [][]byte{[]byte("a"), []byte("b")},
+	}
+	assert.NoError(t, example.Validate(), "distinct elements should pass validation")
+
+	example.Tags = []string{"a", "b", "a", "a"}
+	err := example.Validate()
+	assert.Error(t, err, "duplicate tags should fail validation")
+	assert.Contains(t, err.Error(), "indexes '0' and '2'")
+
+	err = example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 2, "every duplicate occurrence should be reported")
+	assert.Equal(t, "unique", validations.Errors[0].Violation)
+	assert.Equal(t, 2, validations.Errors[0].Index)
+	assert.Equal(t, 3, validations.Errors[1].Index)
+	assert.Contains(t, validations.Errors[1].ErrorMsg, "indexes '0' and '3'")
+
+	example.Tags = nil
+	example.Scopes = []Scope{Scope_SCOPE_READ, Scope_SCOPE_READ}
+	assert.Error(t, example.Validate(), "duplicate enum values should fail validation")
+	example.Scopes = nil
+	example.Blobs = [][]byte{[]byte("a"), []byte("a")}
+	assert.Error(t, example.Validate(), "duplicate bytes values should fail validation")
+}
+
+func TestUnique_KeyedMessages(t *testing.T) {
+	example := &UniqueMessage3{
+		Items: []*KeyedItem{{Id: "a", Value: 1}, {Id: "b", Value: 1}},
+	}
+	assert.NoError(t, example.Validate(), "distinct keys should pass validation")
+
+	example.Items = append(example.Items, &KeyedItem{Id: "a", Value: 2})
+	err := example.ValidateAll()
+	assert.Error(t, err, "duplicate keys should fail validation")
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "Items", validations.Errors[0].Field)
+	assert.Equal(t, 2, validations.Errors[0].Index)
+	assert.Contains(t, validations.Errors[0].ErrorMsg, "unique 'Id'")
+}
+
+func TestUnique_KeyedMessagesWithoutKey(t *testing.T) {
+	example := &UniqueMessage3{
+		Items: []*KeyedItem{{Id: "a"}, nil, {Value: 1}, nil, {Value: 2}},
+	}
+	assert.NoError(t, example.ValidateAll(), "missing elements and keys should not be reported as duplicates")
+	assert.NoError(t, validator.ValidateReflect(example.ProtoReflect()))
+
+	example.Items = append(example.Items, &KeyedItem{Id: "a"})
+	err := example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, 5, validations.Errors[0].Index)
+	assert.Contains(t, validations.Errors[0].ErrorMsg, "found duplicates at indexes '0' and '5'")
+}
+
+func TestItems_CollectionAndElementRules(t *testing.T) {
+	example := &ItemsMessage3{
+		Names:    []string{"ab", "cd", "ef"},
+		Digests:  [][]byte{[]byte("abcd")},
+		Children: []*KeyedItem{{Id: "a"}},
+	}
+	assert.NoError(t, example.Validate(), "collection and element rules should pass")
+
+	example.Names = []string{"ab", "cd", "ef", "gh"}
+	err := example.Validate()
+	assert.Error(t, err, "length_lt should bound the number of elements")
+	assert.Contains(t, err.Error(), "Names")
+
+	example.Names = []string{"a"}
+	assert.Error(t, example.Validate(), "items.length_gt should apply to each element")
+	example.Names = []string{"AB"}
+	assert.Error(t, example.Validate(), "items.regex should apply to each element")
+	example.Names = nil
+
+	example.Digests = nil
+	assert.Error(t, example.Validate(), "length_gt should require at least one element")
+	example.Digests = [][]byte{[]byte("abcd"), []byte("abc")}
+	assert.Error(t, example.Validate(), "items.length_eq should apply to each element")
+	example.Digests = [][]byte{[]byte("abcd")}
+
+	example.Children = []*KeyedItem{{Id: "a"}, nil}
+	err = example.ValidateAll()
+	assert.Error(t, err, "items.msg_exists should reject nil elements")
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "Children", validations.Errors[0].Field)
+	assert.Equal(t, "empty", validations.Errors[0].Violation)
+	assert.Equal(t, 1, validations.Errors[0].Index)
+	assert.EqualError(t, err, "invalid field Children[1]: message must exist")
+}
+
+func TestMessageRules_Required(t *testing.T) {
+	example := &MessageRules3{Name: "name", Child: &SkippedMessage3{}, Email: "a@b.c"}
+	assert.NoError(t, example.Validate(), "nested messages with skip_nested should not be validated")
+	assert.NoError(t, example.ValidateAll())
+	assert.Error(t, example.Child.Validate(), "skip_nested messages should still validate on their own")
+
+	example.Children = []*SkippedMessage3{{}}
+	example.Disabled = &DisabledMessage3{}
+	assert.NoError(t, example.ValidateAll(), "skipped and disabled messages should not be validated")
+
+	example.Name = ""
+	example.Child = nil
+	err := example.ValidateAll()
+	assert.Error(t, err, "required fields should be set")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 2)
+	assert.Equal(t, "Name", validations.Errors[0].Field)
+	assert.Equal(t, "required", validations.Errors[0].Violation)
+	assert.Equal(t, "Child", validations.Errors[1].Field)
+
+	example2 := &MessageRules2{Email: proto.String("a@b.c")}
+	assert.Error(t, example2.Validate(), "proto2 required fields should be set")
+	example2.Name = proto.String("")
+	assert.NoError(t, example2.Validate(), "proto2 presence should not depend on the value")
+}
+
+func TestMessageRules_FieldGroups(t *testing.T) {
+	example := &MessageRules3{Name: "name", Child: &SkippedMessage3{}}
+	err := example.ValidateAll()
+	assert.Error(t, err, "at least one of Email, Phone should be set")
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "Contact", validations.Errors[0].Field)
+	assert.Equal(t, "at_least_one_of", validations.Errors[0].Violation)
+
+	example.Phone = "123"
+	example.Count = 1
+	assert.NoError(t, example.Validate(), "a single field of an at_most_one_of group can be set")
+	example.Tags = []string{"a"}
+	err = example.Validate()
+	assert.Error(t, err, "at most one of Count, Enabled, Tags can be set")
+	assert.Contains(t, err.Error(), "at most one of the fields Count, Enabled, Tags can be set")
+
+	example2 := &MessageRules2{Name: proto.String("name"), Phone: proto.String("123"), Count: proto.Int32(0), Blob: []byte{}}
+	assert.Error(t, example2.Validate(), "proto2 presence should count set zero values")
+	example2.Blob = nil
+	assert.NoError(t, example2.Validate())
+}
+
+func TestMessageRules_Disabled(t *testing.T) {
+	_, ok := interface{}(&DisabledMessage3{}).(validator.Validator)
+	assert.False(t, ok, "disabled messages should not have a Validate method")
+}
+
+func TestCEL_MessageRules(t *testing.T) {
+	example := &CelMessage3{
+		StartTime:   timestamppb.New(time.Unix(100, 0)),
+		EndTime:     timestamppb.New(time.Unix(200, 0)),
+		MinReplicas: 1,
+		MaxReplicas: 3,
+		Type:        PaymentType_PAYMENT_TYPE_CARD,
+		CardNumber:  "4242",
+	}
+	assert.NoError(t, example.Validate(), "all expressions hold")
+
+	example.EndTime = timestamppb.New(time.Unix(50, 0))
+	err := example.Validate()
+	assert.Error(t, err, "end_time before start_time should fail validation")
+	assert.Equal(t, "end_time must be after start_time", err.Error())
+
+	example.MaxReplicas = 0
+	example.CardNumber = ""
+	err = example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 3, "every violated expression should be reported")
+	assert.Equal(t, "time_range", validations.Errors[0].Violation)
+	assert.Equal(t, "replicas", validations.Errors[1].Violation)
+	assert.Contains(t, validations.Errors[1].ErrorMsg, "this.max_replicas >= this.min_replicas")
+	assert.Equal(t, "card_number", validations.Errors[2].Violation)
+	assert.Equal(t, "card_number is required for card payments", validations.Errors[2].ErrorMsg)
+}
+
+func TestCEL_FieldRules(t *testing.T) {
+	example := &CelMessage3{
+		MaxReplicas: 20,
+		Labels:      []string{"x-a", "b"},
+		Limits:      map[string]int64{"cpu": 0},
+	}
+	err := example.Validate()
+	assert.Error(t, err, "field expressions should be evaluated")
+	assert.Contains(t, err.Error(), "MaxReplicas")
+
+	err = example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "MaxReplicas", validations.Errors[0].Field)
+	assert.Equal(t, "max_replicas", validations.Errors[0].Violation)
+	assert.Equal(t, "must be at most 10", validations.Errors[0].ErrorMsg)
+	assert.Equal(t, "Labels", validations.Errors[1].Field)
+	assert.Equal(t, "cel", validations.Errors[1].Violation)
+	assert.Equal(t, "Limits", validations.Errors[2].Field)
+}
+
+func TestCEL_InvalidExpression(t *testing.T) {
+	md := (&CelMessage3{}).ProtoReflect().Descriptor()
+	_, err := validator.CompileCELRule(md, &validator.CELRule{Expression: "this.unknown_field > 1"})
+	assert.Error(t, err, "unknown fields should fail type checking")
+	_, err = validator.CompileCELRule(md, &validator.CELRule{ProtoName: "min_replicas", Expression: "this + 1u"})
+	assert.Error(t, err, "expressions should evaluate to a bool")
+}
+
+func TestConditional_RequiredIf(t *testing.T) {
+	example := &ConditionalMessage3{Method: DeliveryMethod_SHIP, Address: &ShippingAddress{Street: "Main St"}}
+	assert.NoError(t, example.Validate(), "address is set for shipping")
+
+	example.Address = nil
+	err := example.Validate()
+	assert.Error(t, err, "address is required for shipping")
+	assert.Contains(t, err.Error(), "must be set when Method is SHIP")
+
+	example = &ConditionalMessage3{Method: DeliveryMethod_PICKUP, GiftMessage: "hi"}
+	err = example.ValidateAll()
+	assert.Error(t, err, "gift wrap is required along with a gift message")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "GiftWrap", validations.Errors[0].Field)
+	assert.Equal(t, "required_if", validations.Errors[0].Violation)
+	assert.Equal(t, "value must be set when GiftMessage is set", validations.Errors[0].ErrorMsg)
+
+	example2 := &ConditionalMessage2{}
+	assert.NoError(t, example2.Validate(), "unset proto2 fields don't match any value")
+	example2.Mode = proto.Int32(0)
+	assert.Error(t, example2.Validate(), "token is required in mode 0")
+}
+
+func TestConditional_RequiredUnlessAndForbiddenIf(t *testing.T) {
+	example := &ConditionalMessage3{Method: DeliveryMethod_DIGITAL, Email: "a@b.c"}
+	assert.NoError(t, example.Validate())
+
+	example.Email = ""
+	example.Address = &ShippingAddress{Street: "Main St"}
+	example.Priority = 1
+	err := example.ValidateAll()
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 3)
+	assert.Equal(t, "Address", validations.Errors[0].Field)
+	assert.Equal(t, "forbidden_if", validations.Errors[0].Violation)
+	assert.Equal(t, "value must not be set when Method is PICKUP or DIGITAL", validations.Errors[0].ErrorMsg)
+	assert.Equal(t, "Email", validations.Errors[1].Field)
+	assert.Equal(t, "required_unless", validations.Errors[1].Violation)
+	assert.Equal(t, "Priority", validations.Errors[2].Field)
+	assert.Equal(t, "forbidden_if", validations.Errors[2].Violation)
+
+	example = &ConditionalMessage3{Method: DeliveryMethod_SHIP, Address: &ShippingAddress{Street: "Main St"}, Express: true, Priority: 1}
+	assert.NoError(t, example.Validate(), "priority is allowed for express shipping")
+}
+
+func TestGroups_DefaultGroup(t *testing.T) {
+	example := &GroupsUser3{Id: "not-empty", Name: "alice", Email: "alice@other.com", Address: &GroupsAddress3{City: "Springfield"}}
+	assert.NoError(t, example.Validate(), "rules of other groups must not be validated by Validate")
+	assert.NoError(t, example.ValidateAll(), "rules of other groups must not be validated by ValidateAll")
+	assert.NoError(t, example.ValidateGroups(), "no group validates the default group")
+
+	example.Name = ""
+	assert.Error(t, example.ValidateGroups(validator.DefaultGroup))
+	example.Id = ""
+	assert.NoError(t, example.ValidateGroups("create"), "default rules must not be validated for other groups")
+}
+
+func TestGroups_CreateAndUpdate(t *testing.T) {
+	example := &GroupsUser3{Name: "alice"}
+	assert.NoError(t, example.ValidateGroups("create"))
+	err := example.ValidateAllGroups("update")
+	assert.Error(t, err, "update requires an id and a version")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 2)
+	assert.Equal(t, "regex", validations.Errors[0].Violation)
+	assert.Equal(t, "int_gt", validations.Errors[1].Violation)
+
+	example.Id = "u-1"
+	example.Version = 1
+	assert.NoError(t, example.ValidateAllGroups("update"))
+	assert.Error(t, example.ValidateGroups("create"), "create requires an empty id")
+
+	example.Version = 100
+	err = example.ValidateAllGroups("update", validator.DefaultGroup)
+	assert.Error(t, err, "cel rules of a field validator belong to its groups")
+	assert.Equal(t, "version", err.(*validator.ValidationErrors).Errors[0].Violation)
+}
+
+func TestGroups_NestedAndCEL(t *testing.T) {
+	example := &GroupsUser3{Id: "u-1", Name: "alice", Email: "alice@example.com", Address: &GroupsAddress3{City: "Springfield"}}
+	err := example.ValidateAllGroups("internal")
+	assert.Error(t, err, "nested messages are validated for the same groups")
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "Address", validations.Errors[0].Field)
+	assert.Equal(t, "length_lt", validations.Errors[0].Errors.Errors[0].Violation)
+
+	example.Address.City = "Paris"
+	assert.NoError(t, example.ValidateGroups("internal"))
+	example.Email = "alice@other.com"
+	err = example.ValidateGroups("internal")
+	assert.Error(t, err, "cel rules tagged with a group are validated for that group")
+	assert.NoError(t, example.Validate())
+}
+
+func TestMask_OnlyListedFields(t *testing.T) {
+	example := &MaskUser3{Id: "u1", DisplayName: "Al"}
+	assert.Error(t, example.ValidateAll(), "a partial message fails the validation of every field")
+	assert.NoError(t, example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"id"}}))
+
+	err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"display_name"}})
+	assert.Error(t, err, "listed fields are validated")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "DisplayName", validations.Errors[0].Field)
+
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"*"}})
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 2, "the * path validates the whole message")
+	err = example.ValidateMask(nil)
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 2, "an empty mask validates the whole message")
+
+	example.Contact = &MaskUser3_Nickname{Nickname: "far too long nickname"}
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"nickname"}})
+	assert.Error(t, err, "oneof fields are listed by their name")
+	assert.Equal(t, "Nickname", err.(*validator.ValidationErrors).Errors[0].Field)
+}
+
+func TestMask_NestedPaths(t *testing.T) {
+	example := &MaskUser3{
+		Id:                "u1",
+		Address:           &MaskAddress3{Zip: "abc"},
+		PreviousAddresses: []*MaskAddress3{{Street: "Main Street", Zip: "123"}},
+	}
+	err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"address.zip"}})
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "Address", validations.Errors[0].Field)
+	assert.Len(t, validations.Errors[0].Errors.Errors, 1, "only address.zip should be reported")
+	assert.Equal(t, "Zip", validations.Errors[0].Errors.Errors[0].Field)
+
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"address"}})
+	assert.Len(t, err.(*validator.ValidationErrors).Errors[0].Errors.Errors, 2, "a listed message is validated as a whole")
+
+	assert.NoError(t, example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"id", "previous_addresses.street"}}))
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"previous_addresses.zip"}})
+	assert.Error(t, err, "paths of repeated messages apply to every element")
+}
+
+func TestMask_MatchesValidateAll(t *testing.T) {
+	example := &MaskUser3{
+		DisplayName:       "Al",
+		Address:           &MaskAddress3{Street: "Main Street", Zip: "abc"},
+		PreviousAddresses: []*MaskAddress3{{Street: "Main Street", Zip: "12345"}, {Zip: "123"}},
+		Contact:           &MaskUser3_Nickname{Nickname: "far too long nickname"},
+		Login:             "Not a login",
+	}
+	all := violations(example.ValidateAll())
+	var masked []string
+	seen := map[string]bool{}
+	fields := example.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		paths := []string{string(fields.Get(i).Name())}
+		err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: paths})
+		assert.Equal(t, violations(validator.ValidateMask(example, paths)), violations(err), "the generated and the reflective validations of %v should match", paths)
+		for _, violation := range violations(err) {
+			assert.Contains(t, all, violation, "the violations of %v should be violations of the whole message", paths)
+			if !seen[violation] {
+				seen[violation] = true
+				masked = append(masked, violation)
+			}
+		}
+	}
+	assert.ElementsMatch(t, all, masked, "listing each field in turn should report every violation of the message")
+}
+
+func TestMask_UnknownPaths(t *testing.T) {
+	example := &MaskUser3{Id: "u1", DisplayName: "Al"}
+	err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"id", "dispaly_name"}})
+	assert.EqualError(t, err, "path 'dispaly_name' is not a field of validatortest.MaskUser3")
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"address.zpi"}})
+	assert.EqualError(t, err, "path 'address.zpi' is not a field of validatortest.MaskUser3")
+	assert.NotContains(t, fmt.Sprintf("%T", err), "ValidationErrors")
+}
+
+func TestMask_Warnings(t *testing.T) {
+	example := &SeverityMessage3{Nickname: "far too long"}
+	assert.NoError(t, example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"nickname"}}), "warnings don't fail the validation")
+	validations, err := validator.ValidateMaskWithWarnings(example, []string{"nickname"})
+	assert.NoError(t, err)
+	assert.Empty(t, validations.Errors)
+	assert.Len(t, validations.Warnings, 1)
+	assert.Equal(t, "Nickname", validations.Warnings[0].Field)
+
+	err = example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"name", "nickname"}})
+	assert.Error(t, err)
+	validations = err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "Name", validations.Errors[0].Field)
+	assert.Len(t, validations.Warnings, 1, "the warnings of the listed fields are kept along with the errors")
+	assert.Equal(t, "Nickname", validations.Warnings[0].Field)
+}
+
+func TestMask_UnlistedRulesDontRun(t *testing.T) {
+	example := &CelMessage3{MaxReplicas: 11, Labels: []string{"bad"}}
+	assert.NoError(t, example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"min_replicas"}}))
+	err := example.ValidateMask(&fieldmaskpb.FieldMask{Paths: []string{"max_replicas"}})
+	assert.Equal(t, []string{"MaxReplicas[0] max_replicas: must be at most 10"}, violations(err), "message cel expressions are left out")
+
+	// the nested message type has invalid rules, which are only read when one of its fields is listed
+	nestedOptions := &descriptorpb.MessageOptions{}
+	proto.SetExtension(nestedOptions, validator.E_Message, &validator.MessageValidator{Required: []string{"missing"}})
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("unlisted_rules.proto"),
+		Package:    proto.String("unlistedrules"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validator.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Parent"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   proto.String("name"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:     proto.String("child"),
+				Number:   proto.Int32(2),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".unlistedrules.Child"),
+			}},
+		}, {
+			Name:    proto.String("Child"),
+			Options: nestedOptions,
+		}},
+	}, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	parent := dynamicpb.NewMessage(fd.Messages().ByName("Parent"))
+	child := fd.Messages().ByName("Parent").Fields().ByName("child")
+	parent.Set(child, protoreflect.ValueOfMessage(dynamicpb.NewMessage(child.Message())))
+	assert.NoError(t, validator.ValidateMask(parent, []string{"name"}))
+	assert.EqualError(t, validator.ValidateMask(parent, []string{"child"}), "field missing referenced by validator.message required does not exist in unlistedrules.Child")
+}
+
+func TestMask_FieldMaskTarget(t *testing.T) {
+	example := &UpdateMaskUser3Request{}
+	assert.NoError(t, example.Validate(), "an unset field mask is valid")
+	example.UpdateMask = &fieldmaskpb.FieldMask{Paths: []string{"display_name", "address.zip", "*"}}
+	assert.NoError(t, example.Validate())
+
+	example.UpdateMask.Paths = []string{"address.country"}
+	err := example.Validate()
+	assert.Error(t, err, "unknown paths should fail validation")
+	assert.Contains(t, err.Error(), "path 'address.country' is not a field of validatortest.MaskUser3")
+
+	example.UpdateMask.Paths = []string{"previous_addresses.zip"}
+	assert.Error(t, example.Validate(), "paths cannot go through repeated fields")
+	err = example.ValidateAll()
+	assert.Equal(t, "field_mask_target", err.(*validator.ValidationErrors).Errors[0].Violation)
+}
+
+func TestTransition_ImmutableAndMonotonic(t *testing.T) {
+	old := &Document3{CreatedBy: "alice", Version: 1, Owner: &DocumentOwner3{Name: "alice"}, Tags: []string{"a"}, Score: 10}
+	updated := proto.Clone(old).(*Document3)
+	updated.Version = 2
+	updated.Score = 5
+	assert.NoError(t, updated.ValidateTransition(old))
+	assert.NoError(t, updated.ValidateTransition(nil), "there is nothing to compare to without a previous version")
+
+	updated.CreatedBy = "bob"
+	updated.Version = 1
+	updated.Owner.Name = "bob"
+	updated.Tags = append(updated.Tags, "b")
+	updated.Score = 11
+	err := updated.ValidateTransition(old)
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 5)
+	assert.Equal(t, "CreatedBy", validations.Errors[0].Field)
+	assert.Equal(t, "immutable", validations.Errors[0].Violation)
+	assert.Equal(t, "monotonic", validations.Errors[1].Violation)
+	assert.Equal(t, "value '1' must be greater than the previous value '1'", validations.Errors[1].ErrorMsg)
+	assert.Equal(t, "Owner", validations.Errors[2].Field)
+	assert.Equal(t, "Tags", validations.Errors[3].Field)
+	assert.Equal(t, "Score", validations.Errors[4].Field)
+}
+
+func TestTransition_EnumStates(t *testing.T) {
+	transition := func(from, to DocumentState) error {
+		return (&Document3{State: to, Version: 2}).ValidateTransition(&Document3{State: from, Version: 1})
+	}
+	assert.NoError(t, transition(DocumentState_DOCUMENT_STATE_PENDING, DocumentState_DOCUMENT_STATE_ACTIVE))
+	assert.NoError(t, transition(DocumentState_DOCUMENT_STATE_ACTIVE, DocumentState_DOCUMENT_STATE_DONE))
+	assert.NoError(t, transition(DocumentState_DOCUMENT_STATE_DONE, DocumentState_DOCUMENT_STATE_DONE), "keeping the same state is allowed")
+
+	err := transition(DocumentState_DOCUMENT_STATE_PENDING, DocumentState_DOCUMENT_STATE_DONE)
+	assert.Error(t, err, "states cannot be skipped")
+	validations := err.(*validator.ValidationErrors)
+	assert.Equal(t, "transitions", validations.Errors[0].Violation)
+	assert.Equal(t, "value 'DOCUMENT_STATE_DONE' must be a valid transition from 'DOCUMENT_STATE_PENDING'", validations.Errors[0].ErrorMsg)
+	assert.Error(t, transition(DocumentState_DOCUMENT_STATE_DONE, DocumentState_DOCUMENT_STATE_ACTIVE), "states without transitions are final")
+}
+
+func TestTransition_Proto2(t *testing.T) {
+	old := &Document2{CreatedBy: proto.String("alice"), Version: proto.Int32(1), Checksum: []byte{1}}
+	assert.NoError(t, (&Document2{CreatedBy: proto.String("alice"), Version: proto.Int32(1), Checksum: []byte{1}}).ValidateTransition(old))
+	err := (&Document2{Checksum: []byte{2}}).ValidateTransition(old)
+	assert.Error(t, err)
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 3)
+}
+
+func TestTransition_WarningsAndGroups(t *testing.T) {
+	old := &Document3{Version: 1, Title: "draft", Slug: "draft"}
+	updated := &Document3{Version: 2, Title: "final", Slug: "final"}
+	assert.NoError(t, updated.ValidateTransition(old), "warnings and rules of other groups don't fail the transition")
+}
+
+func TestTransition_Nested(t *testing.T) {
+	old := &Folder3{Document: &Document3{CreatedBy: "alice", Version: 1}, Owner: &DocumentOwner3{Name: "alice"}}
+	assert.NoError(t, (&Folder3{Document: &Document3{CreatedBy: "alice", Version: 2}, Owner: &DocumentOwner3{Name: "bob"}}).ValidateTransition(old))
+	assert.NoError(t, (&Folder3{}).ValidateTransition(old), "there is nothing to compare to without a nested message")
+
+	err := (&Folder3{Document: &Document3{CreatedBy: "bob", Version: 2}}).ValidateTransition(old)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid field Document.CreatedBy: value must not change", err.Error())
+
+	_, ok := interface{}(&DocumentOwner3{}).(interface {
+		ValidateTransition(*DocumentOwner3) error
+	})
+	assert.False(t, ok, "messages without transition rules have no ValidateTransition")
+}
+
+func TestSeverity_WarningsDontFail(t *testing.T) {
+	example := &SeverityMessage3{
+		Name:     "a rather long name",
+		Nickname: "too long",
+		Tags:     []string{"ok", "NOT-OK"},
+		Nested:   &SeverityNested3{},
+	}
+	assert.NoError(t, example.Validate(), "warnings are not checked by Validate")
+	assert.NoError(t, example.ValidateAll(), "warnings don't fail ValidateAll")
+
+	validations := example.ValidateAllWithWarnings()
+	assert.False(t, validations.IsError())
+	assert.Len(t, validations.Warnings, 5)
+	assert.Equal(t, "Age", validations.Warnings[0].Field)
+	assert.Equal(t, "required_if", validations.Warnings[0].Violation)
+	assert.Equal(t, "Nickname", validations.Warnings[1].Field)
+	assert.Equal(t, "Tags", validations.Warnings[2].Field)
+	assert.Equal(t, "regex", validations.Warnings[2].Violation)
+	assert.Equal(t, "Nested", validations.Warnings[3].Field)
+	assert.Equal(t, "Code", validations.Warnings[3].Errors.Warnings[0].Field)
+	assert.Equal(t, "short_name", validations.Warnings[4].Violation)
+}
+
+func TestSeverity_ErrorsKeepWarnings(t *testing.T) {
+	example := &SeverityMessage3{Nickname: "too long"}
+	err := example.ValidateAll()
+	assert.Error(t, err, "errors fail ValidateAll")
+	validations := err.(*validator.ValidationErrors)
+	assert.Len(t, validations.Errors, 1)
+	assert.Equal(t, "Name", validations.Errors[0].Field)
+	assert.Len(t, validations.Warnings, 2, "warnings are reported along with the errors")
+}
+
+func TestSeverity_RepeatedField(t *testing.T) {
+	example := &SeverityMessage3{Name: "alice", Aliases: []string{"a", "b", "c"}}
+	assert.NoError(t, example.ValidateAll(), "warnings on repeated fields don't fail ValidateAll")
+	validations := example.ValidateAllWithWarnings()
+	assert.Len(t, validations.Warnings, 1)
+	assert.Equal(t, "Aliases", validations.Warnings[0].Field)
+	assert.Equal(t, "repeated_count_max", validations.Warnings[0].Violation)
+
+	assert.NoError(t, validator.ValidateReflect(example.ProtoReflect()))
+}
+
+func TestShadowPolicy_Rules(t *testing.T) {
+	var recorded []string
+	policy := validator.NewShadowPolicy(func(message string, violation *validator.ValidationError) {
+		recorded = append(recorded, message+"."+violation.Field+":"+violation.Violation)
+	})
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Alice", Address: &MaskAddress3{Street: "Main Street", Zip: "abc"}}
+	assert.Error(t, policy.CallValidatorsIfExists(example), "nothing is shadowed yet")
+	assert.Empty(t, recorded)
+
+	policy.ShadowRule("regex", true)
+	assert.NoError(t, policy.CallValidatorsIfExists(example), "shadowed violations are not returned")
+	assert.Equal(t, []string{"validatortest.MaskAddress3.Zip:regex"}, recorded)
+
+	policy.ShadowRule("regex", false)
+	assert.Error(t, policy.CallValidatorsIfExists(example))
+}
+
+func TestShadowPolicy_MessagesAndPackages(t *testing.T) {
+	var recorded []string
+	policy := validator.NewShadowPolicy(func(message string, violation *validator.ValidationError) {
+		recorded = append(recorded, message+"."+violation.Field)
+	})
+	example := &MaskUser3{DisplayName: "Al", PreviousAddresses: []*MaskAddress3{{Zip: "123"}}}
+
+	policy.ShadowMessage("validatortest.MaskAddress3", true)
+	err := policy.CallValidatorsIfExists(example)
+	assert.Error(t, err)
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 3, "only the violations of MaskUser3 are returned")
+	assert.Equal(t, []string{"validatortest.MaskAddress3.Street", "validatortest.MaskAddress3.Zip"}, recorded)
+
+	recorded = nil
+	policy.ShadowPackage("validatortest", true)
+	assert.NoError(t, policy.CallValidatorsIfExists(example))
+	assert.Len(t, recorded, 4, "the violations of nested messages are recorded with the field holding them")
+
+	var nilPolicy *validator.ShadowPolicy
+	assert.Error(t, nilPolicy.CallValidatorsIfExists(example), "a nil policy shadows nothing")
+}
+
+func TestObserver_Context(t *testing.T) {
+	observer := &validator.MemoryObserver{}
+	ctx := validator.WithObserver(context.Background(), observer)
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Al", PreviousAddresses: []*MaskAddress3{{Street: "Main Street", Zip: "12345"}, {Zip: "abc"}}}
+	assert.Error(t, validator.CallObservedValidatorsIfExists(ctx, example))
+
+	assert.Equal(t, 1, observer.Validations("validatortest.MaskUser3"))
+	assert.Len(t, observer.Durations("validatortest.MaskUser3"), 1)
+	assert.Equal(t, []validator.ObservedViolation{
+		{Message: "validatortest.MaskUser3", Path: "display_name", Kind: "length_gt"},
+		{Message: "validatortest.MaskUser3", Path: "previous_addresses[1].street", Kind: "string_not_empty"},
+		{Message: "validatortest.MaskUser3", Path: "previous_addresses[1].zip", Kind: "regex"},
+	}, observer.Violations())
+
+	observer.Reset()
+	example.DisplayName = "Alice"
+	example.PreviousAddresses = nil
+	assert.NoError(t, validator.CallObservedValidatorsIfExists(ctx, example))
+	assert.Equal(t, 1, observer.Validations("validatortest.MaskUser3"))
+	assert.Empty(t, observer.Violations())
+}
+
+func TestObserver_Warnings(t *testing.T) {
+	observer := &validator.MemoryObserver{}
+	example := &SeverityMessage3{Name: "alice", Nickname: "too long", Age: 30, Tags: []string{"NOT-OK"}, Nested: &SeverityNested3{}}
+
+	err := validator.ValidateContextWithWarnings(context.Background(), example, validator.Observe(observer), validator.Groups(validator.DefaultGroup, "create"))
+	assert.Error(t, err, "the warnings of a valid message are returned")
+	validations := err.(*validator.ValidationErrors)
+	assert.False(t, validations.IsError())
+	assert.Len(t, validations.Warnings, 3)
+	assert.NoError(t, validator.ValidateContext(context.Background(), example, validator.Observe(observer)), "warnings don't fail ValidateContext")
+
+	assert.Empty(t, observer.Violations())
+	assert.Equal(t, []validator.ObservedViolation{
+		{Message: "validatortest.SeverityMessage3", Path: "nickname", Kind: "length_lt"},
+		{Message: "validatortest.SeverityMessage3", Path: "tags[0]", Kind: "regex"},
+		{Message: "validatortest.SeverityMessage3", Path: "nested.code", Kind: "string_not_empty"},
+	}, observer.Warnings()[:3])
+	assert.Len(t, observer.Warnings(), 6, "the observer is told about the warnings on every path")
+
+	observer.Reset()
+	assert.NoError(t, validator.CallObservedValidatorsIfExists(validator.WithObserver(context.Background(), observer), example))
+	assert.Len(t, observer.Warnings(), 3)
+}
+
+func TestContext_GroupsAndNested(t *testing.T) {
+	example := &GroupsUser3{Id: "u-1", Name: "alice", Email: "alice@example.com", Address: &GroupsAddress3{City: "Springfield"}}
+	assert.NoError(t, example.ValidateContext(context.Background()))
+	err := example.ValidateContext(context.Background(), validator.Groups("internal"))
+	assert.Equal(t, example.ValidateAllGroups("internal"), err, "nested messages are validated for the groups of the options")
+
+	example.Address.City = "Paris"
+	assert.NoError(t, validator.ValidateContext(context.Background(), example, validator.Groups("internal")))
+	example.Email = "alice@example.org"
+	err = validator.ValidateContext(context.Background(), example, validator.Groups("internal"))
+	assert.Error(t, err)
+	assert.Equal(t, "email", err.(*validator.ValidationErrors).Errors[0].Violation)
+}
+
+func TestContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Alice"}
+	assert.Equal(t, context.Canceled, example.ValidateContext(ctx))
+}
+
+func TestContext_Observer(t *testing.T) {
+	observer := &validator.MemoryObserver{}
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Al"}
+	assert.Error(t, example.ValidateContext(context.Background(), validator.Observe(observer)))
+	assert.Equal(t, 1, observer.Validations("validatortest.MaskUser3"), "nested messages are not reported")
+	assert.Equal(t, []validator.ObservedViolation{{Message: "validatortest.MaskUser3", Path: "display_name", Kind: "length_gt"}}, observer.Violations())
+}
+
+func TestLimits_RepeatedItemsAndErrors(t *testing.T) {
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Alice"}
+	for i := 0; i < 1000; i++ {
+		example.PreviousAddresses = append(example.PreviousAddresses, &MaskAddress3{Zip: "abc"})
+	}
+	err := example.ValidateContext(context.Background(), validator.MaxRepeatedItems(3))
+	assert.Error(t, err)
+	validations := err.(*validator.ValidationErrors)
+	assert.True(t, validations.Truncated())
+	assert.Len(t, validations.Errors, 4, "3 inspected addresses and the marker")
+	assert.Equal(t, validator.TooManyErrors, validations.Errors[3].Violation)
+
+	err = example.ValidateContext(context.Background(), validator.MaxErrors(5))
+	assert.Error(t, err)
+	validations = err.(*validator.ValidationErrors)
+	assert.True(t, validations.Truncated())
+	assert.Len(t, validations.Errors, 4, "the third address only holds the fifth violation")
+	assert.Len(t, validations.Errors[2].Errors.Errors, 1)
+
+	example.PreviousAddresses = example.PreviousAddresses[:2]
+	err = example.ValidateContext(context.Background(), validator.MaxErrors(5), validator.MaxRepeatedItems(3))
+	assert.Error(t, err)
+	assert.False(t, err.(*validator.ValidationErrors).Truncated(), "limits which are not hit don't truncate")
+	assert.Len(t, err.(*validator.ValidationErrors).Errors, 2)
+}
+
+func TestLimits_Depth(t *testing.T) {
+	example := &UpdateMaskUser3Request{User: &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Alice", Address: &MaskAddress3{Street: "Main Street", Zip: "12345"}}}
+	assert.NoError(t, example.ValidateContext(context.Background(), validator.MaxDepth(2)))
+	err := example.ValidateContext(context.Background(), validator.MaxDepth(1))
+	assert.Error(t, err, "a message which is not traversed fails the validation")
+	assert.Equal(t, []*validator.ValidationError{{Violation: validator.TooManyErrors, ErrorMsg: "too many errors, the message was only partially validated"}}, err.(*validator.ValidationErrors).Errors)
+	assert.NoError(t, example.ValidateContext(context.Background()), "there are no limits by default")
+
+	validations, err := example.ValidateAllContextWithWarnings(validator.WithOptions(context.Background(), validator.MaxDepth(1)))
+	assert.NoError(t, err)
+	assert.True(t, validations.Truncated(), "the generated code marks the validated message")
+	assert.NoError(t, validator.ValidateReflect(example.ProtoReflect(), validator.MaxDepth(2)))
+	assert.True(t, validator.ValidateReflect(example.ProtoReflect(), validator.MaxDepth(1)).(*validator.ValidationErrors).Truncated(), "the reflective validation counts the same depth")
+
+	example.User.Address.Zip = "abc"
+	validations, err = example.ValidateAllContextWithWarnings(validator.WithOptions(context.Background(), validator.MaxErrors(1)))
+	assert.NoError(t, err)
+	assert.Len(t, validations.Errors, 1, "the nested messages are not marked")
+	assert.False(t, validations.Errors[0].Errors.Truncated())
+	assert.False(t, validations.Truncated(), "the limit is not hit")
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Al", PreviousAddresses: []*MaskAddress3{{Street: "Main Street", Zip: "12345"}, {Street: "Main Street", Zip: "abc"}}}
+	err := example.ValidateAll()
+	assert.Equal(t, "invalid field DisplayName: value 'Al' must have a length greater than '2'; "+
+		"invalid field PreviousAddresses[1].Zip: value 'abc' must be a string conforming to regex \"^[0-9]{5}$\"", err.Error())
+	assert.Equal(t, "invalid field PreviousAddresses[1].Zip: value 'abc' must be a string conforming to regex \"^[0-9]{5}$\"", err.(*validator.ValidationErrors).Errors[1].Error())
+
+	example.PreviousAddresses = nil
+	for i := 0; i < 10; i++ {
+		example.PreviousAddresses = append(example.PreviousAddresses, &MaskAddress3{Street: "Main Street"})
+	}
+	err = example.ValidateAll()
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid field DisplayName: "))
+	assert.True(t, strings.HasSuffix(err.Error(), "invalid field PreviousAddresses[3].Zip: value '' must be a string conforming to regex \"^[0-9]{5}$\" (and 6 more)"), err.Error())
+}
+
+func TestValidationErrors_IsAs(t *testing.T) {
+	example := &MaskUser3{Id: "u1", Email: "a@example.com", DisplayName: "Alice", PreviousAddresses: []*MaskAddress3{{Zip: "12345"}}}
+	err := example.ValidateAll()
+	var violation *validator.ValidationError
+	assert.True(t, errors.As(err, &violation))
+	assert.Equal(t, "PreviousAddresses", violation.Field)
+	var nested *validator.ValidationErrors
+	assert.True(t, errors.As(violation, &nested))
+	assert.Equal(t, "string_not_empty", nested.Errors[0].Violation)
+
+	cause := errors.New("unavailable")
+	validations := &validator.ValidationErrors{}
+	validations.AddValidationError("Nested", "message", &validator.ValidationErrors{Errors: []*validator.ValidationError{{Field: "Lookup", Violation: "custom", ErrorMsg: "failed"}}})
+	validations.AddValidationError("Lookup", "custom", cause)
+	assert.True(t, errors.Is(validations, cause))
+	assert.Equal(t, "invalid field Nested.Lookup: failed; invalid field Lookup: unavailable", validations.Error())
+}
+
+// TestReflect_Conformance checks that ValidateReflect reports the same violations as the generated code, on random
+// messages of every test proto, whether they are generated or dynamic messages.
+func TestReflect_Conformance(t *testing.T) {
+	var types []protoreflect.MessageType
+	protoregistry.GlobalTypes.RangeMessages(func(mt protoreflect.MessageType) bool {
+		if mt.Descriptor().ParentFile().Package() == "validatortest" {
+			types = append(types, mt)
+		}
+		return true
+	})
+	assert.NotEmpty(t, types)
+	rnd := rand.New(rand.NewSource(1))
+	for _, mt := range types {
+		for i := 0; i < 300; i++ {
+			m := mt.New()
+			randomMessage(rnd, types, m, 0)
+			expected := violations(validator.CallValidatorsIfExists(m.Interface()))
+			if !assert.Equal(t, expected, violations(validator.ValidateReflect(m)), "%s{%s}", mt.Descriptor().FullName(), prototext.Format(m.Interface())) {
+				break
+			}
+			data, err := proto.MarshalOptions{AllowPartial: true}.Marshal(m.Interface())
+			assert.NoError(t, err)
+			dynamic := dynamicpb.NewMessage(mt.Descriptor())
+			assert.NoError(t, proto.UnmarshalOptions{AllowPartial: true}.Unmarshal(data, dynamic))
+			if !assert.Equal(t, expected, violations(validator.ValidateReflect(dynamic)), "dynamic %s{%s}", mt.Descriptor().FullName(), prototext.Format(m.Interface())) {
+				break
+			}
+		}
+	}
+}
+
+var pointerPattern = regexp.MustCompile(`0x[0-9a-f]+`)
+
+// violations lists the leaf violations of err with their paths.
+func violations(err error) []string {
+	if err == nil {
+		return nil
+	}
+	validations, ok := err.(*validator.ValidationErrors)
+	if !ok {
+		return []string{err.Error()}
+	}
+	var leaves []string
+	var walk func(prefix string, validations *validator.ValidationErrors)
+	walk = func(prefix string, validations *validator.ValidationErrors) {
+		for _, e := range validations.Errors {
+			path := fmt.Sprintf("%s%s[%d]", prefix, e.Field, e.Index)
+			if e.Errors != nil {
+				walk(path+".", e.Errors)
+				continue
+			}
+			leaves = append(leaves, path+" "+e.Violation+": "+pointerPattern.ReplaceAllString(e.ErrorMsg, "0x"))
+		}
+	}
+	walk("", validations)
+	return leaves
+}
+
+var (
+	randomStrings = []string{"", "a", "ab", "abc", "hello", "u-1", "alice@example.com", "Main Street", "12345", "f47ac10b-58cc-4372-a567-0e02b2c3d479", "0123456789abcdef", "Zoë"}
+	randomInts    = []int64{-1, 0, 1, 2, 5, 10, 11, 42, 99, 100, 101, 1000}
+	randomFloats  = []float64{-1, 0, 0.25, 0.3, 0.35, 0.5, 0.65, 0.7, 0.75, 0.8, 1, 100}
+	randomPaths   = []string{"id", "email", "address.zip", "address.country", "*"}
+)
+
+// randomMessage sets random values to the fields of m.
+func randomMessage(rnd *rand.Rand, types []protoreflect.MessageType, m protoreflect.Message, depth int) {
+	md := m.Descriptor()
+	switch md.FullName() {
+	case "google.protobuf.Any":
+		if depth < 3 && rnd.Intn(4) > 0 {
+			packed := types[rnd.Intn(len(types))].New()
+			randomMessage(rnd, types, packed, depth+1)
+			value, _ := proto.MarshalOptions{AllowPartial: true}.Marshal(packed.Interface())
+			m.Set(md.Fields().ByName("type_url"), protoreflect.ValueOfString("type.googleapis.com/"+string(packed.Descriptor().FullName())))
+			m.Set(md.Fields().ByName("value"), protoreflect.ValueOfBytes(value))
+		} else if rnd.Intn(2) == 0 {
+			m.Set(md.Fields().ByName("type_url"), protoreflect.ValueOfString("type.googleapis.com/unknown.Type"))
+		}
+		return
+	case "google.protobuf.FieldMask":
+		paths := m.Mutable(md.Fields().ByName("paths")).List()
+		for i := rnd.Intn(3); i > 0; i-- {
+			paths.Append(protoreflect.ValueOfString(randomPaths[rnd.Intn(len(randomPaths))]))
+		}
+		return
+	}
+	oneofs := map[protoreflect.FullName]protoreflect.FieldDescriptor{}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		fields := md.Oneofs().Get(i).Fields()
+		if n := rnd.Intn(fields.Len() + 1); n < fields.Len() {
+			oneofs[md.Oneofs().Get(i).FullName()] = fields.Get(n)
+		}
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if od := fd.ContainingOneof(); od != nil {
+			if oneofs[od.FullName()] != fd {
+				continue
+			}
+		} else if rnd.Intn(3) == 0 {
+			continue
+		}
+		switch {
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for n := rnd.Intn(5); n > 0; n-- {
+				if fd.Message() != nil {
+					item := list.NewElement()
+					randomMessage(rnd, types, item.Message(), depth+1)
+					list.Append(item)
+				} else {
+					list.Append(randomValue(rnd, fd))
+				}
+			}
+		case fd.IsMap():
+			entries := m.Mutable(fd).Map()
+			for n := rnd.Intn(3); n > 0; n-- {
+				key := randomValue(rnd, fd.MapKey()).MapKey()
+				if fd.MapValue().Message() != nil {
+					value := entries.NewValue()
+					randomMessage(rnd, types, value.Message(), depth+1)
+					entries.Set(key, value)
+				} else {
+					entries.Set(key, randomValue(rnd, fd.MapValue()))
+				}
+			}
+		case fd.Message() != nil:
+			if depth < 3 {
+				randomMessage(rnd, types, m.Mutable(fd).Message(), depth+1)
+			}
+		default:
+			m.Set(fd, randomValue(rnd, fd))
+		}
+	}
+}
+
+func randomValue(rnd *rand.Rand, fd protoreflect.FieldDescriptor) protoreflect.Value {
+	n := randomInts[rnd.Intn(len(randomInts))]
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(randomStrings[rnd.Intn(len(randomStrings))])
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(randomStrings[rnd.Intn(len(randomStrings))]))
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(rnd.Intn(2) == 0)
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		if i := rnd.Intn(values.Len() + 1); i < values.Len() {
+			return protoreflect.ValueOfEnum(values.Get(i).Number())
+		}
+		return protoreflect.ValueOfEnum(99)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(n))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(n)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(n + 1))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(uint64(n + 1))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(randomFloats[rnd.Intn(len(randomFloats))]))
+	}
+	return protoreflect.ValueOfFloat64(randomFloats[rnd.Intn(len(randomFloats))])
+}
+
+func TestRegistry(t *testing.T) {
+	v, ok := validator.LookupValidator("validatortest.AnyPayload")
+	assert.True(t, ok)
+	assert.NoError(t, v.Validate(&AnyPayload{Identifier: "abba"}))
+	assert.Error(t, v.Validate(&AnyPayload{Identifier: "999"}))
+	assert.Error(t, v.Validate(&ValidatorMessage3{}), "a message of another type is not validated")
+	_, ok = validator.LookupValidator("validatortest.Unknown")
+	assert.False(t, ok)
+
+	var names []string
+	validator.RangeValidators(func(v *validator.RegisteredValidator) bool {
+		names = append(names, string(v.FullName))
+		return true
+	})
+	assert.Contains(t, names, "validatortest.AnyPayload")
+	assert.Contains(t, names, "validatortest.ValidatorMessage3.EmbeddedMessage")
+	assert.IsIncreasing(t, names)
+
+	assert.NoError(t, validator.ValidateAny(mustPackAny(t, &AnyPayload{Identifier: "abba"})))
+	err := validator.ValidateAny(mustPackAny(t, &AnyPayload{Identifier: "999"}))
+	assert.IsType(t, &validator.ValidationErrors{}, err)
+	assert.Contains(t, err.Error(), "Identifier")
+	assert.EqualError(t, validator.ValidateAny(&anypb.Any{TypeUrl: "type.googleapis.com/validatortest.Unknown"}), "no validator registered for type 'validatortest.Unknown'")
+	assert.Error(t, validator.ValidateAny(&anypb.Any{TypeUrl: "type.googleapis.com/validatortest.AnyPayload", Value: []byte{0xff}}))
+}
+
+func TestValidationRules(t *testing.T) {
+	rules := (&OneOfMessage3{}).ValidationRules()
+	assert.Equal(t, "validatortest.OneOfMessage3", string(rules.FullName))
+	assert.Nil(t, rules.Message)
+	assert.Equal(t, int64(10), rules.Fields["SomeInt"].GetIntGt())
+	assert.Equal(t, "^[a-z]{2,5}$", rules.Fields["five_regex"].GetRegex())
+	assert.NotContains(t, rules.Fields, "one_msg")
+	assert.True(t, rules.Oneofs["something"].GetRequired())
+	assert.NotContains(t, rules.Oneofs, "type")
+
+	rules = (&MessageRules3{}).ValidationRules()
+	assert.Equal(t, []string{"Name", "Child"}, rules.Message.GetRequired())
+	assert.Equal(t, "Contact", rules.Message.GetAtLeastOneOf()[0].GetName())
+
+	rules.Fields["SomeInt"] = nil
+	assert.Equal(t, int64(10), validator.DescriptorRules((&OneOfMessage3{}).ProtoReflect().Descriptor()).Fields["SomeInt"].GetIntGt())
+	assert.Nil(t, validator.MessageRules("not a message"))
+}
+
+// generatorRequest returns a request generating the given files, with the descriptors of all of their imports.
+func generatorRequest(t *testing.T, parameter string, files ...protoreflect.FileDescriptor) *plugin_go.CodeGeneratorRequest {
+	req := &plugin_go.CodeGeneratorRequest{Parameter: gogoproto.String(parameter)}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		for i := 0; i < fd.Imports().Len(); i++ {
+			add(fd.Imports().Get(i).FileDescriptor)
+		}
+		data, err := proto.Marshal(protodesc.ToFileDescriptorProto(fd))
+		assert.NoError(t, err)
+		file := &descriptor.FileDescriptorProto{}
+		assert.NoError(t, gogoproto.Unmarshal(data, file))
+		req.ProtoFile = append(req.ProtoFile, file)
+	}
+	for _, fd := range files {
+		add(fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+	}
+	return req
+}
+
+// generatedJSON returns the content of a file of a generator response, decoded from JSON.
+func generatedJSON(t *testing.T, resp *plugin_go.CodeGeneratorResponse, name string) map[string]interface{} {
+	for _, f := range resp.File {
+		if f.GetName() == name {
+			var content map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(f.GetContent()), &content))
+			return content
+		}
+	}
+	t.Fatalf("%s was not generated", name)
+	return nil
+}
+
+// generateGo runs the Go generator with opts on files and returns the generated code, or the error the generator
+// failed with. The generator exits on errors, so it runs in a child process, TestGoGeneratorProcess.
+func generateGo(t *testing.T, opts validatorplugin.Options, files ...protoreflect.FileDescriptor) (string, error) {
+	optsJSON, err := json.Marshal(opts)
+	assert.NoError(t, err)
+	data, err := gogoproto.Marshal(generatorRequest(t, "", files...))
+	assert.NoError(t, err)
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGoGeneratorProcess$")
+	cmd.Env = append(os.Environ(), "VALIDATOR_GO_GENERATOR_OPTIONS="+string(optsJSON))
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.New(strings.TrimSpace(stderr.String()))
+	}
+	resp := &plugin_go.CodeGeneratorResponse{}
+	assert.NoError(t, gogoproto.Unmarshal(stdout.Bytes(), resp))
+	var code strings.Builder
+	for _, f := range resp.File {
+		code.WriteString(f.GetContent())
+	}
+	return code.String(), nil
+}
+
+// TestGoGeneratorProcess is the child process of generateGo, it does nothing when run as a test.
+func TestGoGeneratorProcess(t *testing.T) {
+	optsJSON := os.Getenv("VALIDATOR_GO_GENERATOR_OPTIONS")
+	if optsJSON == "" {
+		return
+	}
+	opts := validatorplugin.Options{}
+	if err := json.Unmarshal([]byte(optsJSON), &opts); err != nil {
+		t.Fatal(err)
+	}
+	gen := generator.New()
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gogoproto.Unmarshal(data, gen.Request); err != nil {
+		t.Fatal(err)
+	}
+	gen.CommandLineParameters(gen.Request.GetParameter())
+	gen.WrapTypes()
+	gen.SetPackageNames()
+	gen.BuildTypeNameMap()
+	gen.GeneratePlugin(validatorplugin.NewPluginWithOptions(opts))
+	if data, err = gogoproto.Marshal(gen.Response); err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout.Write(data)
+	os.Exit(0)
+}
+
+func TestJSONSchema(t *testing.T) {
+	resp, err := validatorplugin.GenerateJSONSchema(generatorRequest(t, "", File_validator_proto3_proto, File_validator_proto3_oneof_proto, File_validator_proto3_message_proto), validatorplugin.Options{})
+	assert.NoError(t, err)
+
+	schema := generatedJSON(t, resp, "validatortest.ValidatorMessage3.schema.json")
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, "#/$defs/validatortest.ValidatorMessage3", schema["$ref"])
+	defs := schema["$defs"].(map[string]interface{})
+	message := defs["validatortest.ValidatorMessage3"].(map[string]interface{})
+	properties := message["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": "^.{2,5}$"}, properties["SomeString"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "pattern": "^.{2,5}$"}}, properties["SomeStringRep"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "format": "uint32", "exclusiveMinimum": 10.0}, properties["SomeInt"])
+	assert.Equal(t, map[string]interface{}{"type": "number", "exclusiveMinimum": 0.3, "exclusiveMaximum": 0.7}, properties["StrictSomeDouble"])
+	assert.Equal(t, map[string]interface{}{"type": "number", "minimum": 0.25, "maximum": 0.75}, properties["SomeDouble"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "minLength": 1.0}, properties["SomeNonEmptyString"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer", "format": "int32"}, "minItems": 2.0, "maxItems": 5.0}, properties["Repeated"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "minLength": 3.0, "$comment": byteLengthComment}, properties["SomeStringLtReq"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "maxLength": 11.0, "$comment": byteLengthComment}, properties["SomeStringGtReq"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "minLength": 10.0, "maxLength": 10.0, "$comment": byteLengthComment}, properties["SomeStringEqReq"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "contentEncoding": "base64"}, properties["SomeBytesEqReq"])
+	assert.Equal(t, "uuid", properties["UUID4NotEmpty"].(map[string]interface{})["format"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"zero", "one"}}, properties["someEmbeddedEnum"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/validatortest.ValidatorMessage3.EmbeddedMessage"}, properties["someEmbeddedExists"])
+	assert.Contains(t, message["required"], "someEmbeddedExists")
+	assert.Contains(t, defs, "validatortest.ValidatorMessage3.EmbeddedMessage")
+
+	schema = generatedJSON(t, resp, "validatortest.OneOfMessage3.schema.json")
+	message = schema["$defs"].(map[string]interface{})["validatortest.OneOfMessage3"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"required": []interface{}{"threeInt"}},
+		map[string]interface{}{"required": []interface{}{"fourInt"}},
+		map[string]interface{}{"required": []interface{}{"fiveRegex"}},
+	}, message["oneOf"])
+
+	schema = generatedJSON(t, resp, "validatortest.MessageRules3.schema.json")
+	message = schema["$defs"].(map[string]interface{})["validatortest.MessageRules3"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"Name", "Child"}, message["required"])
+	assert.Len(t, message["allOf"], 2)
+}
+
+const byteLengthComment = "length_gt, length_lt and length_eq count the bytes of the UTF-8 encoding, minLength and maxLength only match them for ASCII strings"
+
+func TestJSONSchema_ByteLengths(t *testing.T) {
+	itemsSchema := func(name string, items *validator.FieldValidator) map[string]interface{} {
+		fd := repeatedRulesFile(t, name, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", &validator.FieldValidator{Items: items})
+		resp, err := validatorplugin.GenerateJSONSchema(generatorRequest(t, "", fd), validatorplugin.Options{})
+		assert.NoError(t, err)
+		schema := generatedJSON(t, resp, "repeatedrules.A.schema.json")
+		message := schema["$defs"].(map[string]interface{})["repeatedrules.A"].(map[string]interface{})
+		return message["properties"].(map[string]interface{})["tags"].(map[string]interface{})["items"].(map[string]interface{})
+	}
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": "^[a-z]+$", "maxLength": 9.0},
+		itemsSchema("ascii_lengths.proto", &validator.FieldValidator{Regex: proto.String("^[a-z]+$"), LengthLt: proto.Int64(10)}),
+		"lengths count the same for ASCII strings")
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": "^[a-zé]+$", "maxLength": 9.0, "$comment": byteLengthComment},
+		itemsSchema("unicode_lengths.proto", &validator.FieldValidator{Regex: proto.String("^[a-zé]+$"), LengthLt: proto.Int64(10)}))
+	assert.Equal(t, byteLengthComment, itemsSchema("unanchored_lengths.proto", &validator.FieldValidator{Regex: proto.String("[a-z]+"), LengthGt: proto.Int64(1)})["$comment"])
+}
+
+func TestOpenAPI(t *testing.T) {
+	req := generatorRequest(t, "", File_validator_proto3_oneof_proto)
+	resp, err := validatorplugin.GenerateOpenAPI(req, validatorplugin.Options{})
+	assert.NoError(t, err)
+	doc := generatedJSON(t, resp, "validator_proto3_oneof.openapi.json")
+	assert.Equal(t, "3.1.0", doc["openapi"])
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	message := schemas["validatortest.OneOfMessage3"].(map[string]interface{})
+	properties := message["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/validatortest.ExternalMsg"}, properties["oneMsg"])
+	assert.Len(t, message["oneOf"], 3)
+	assert.Contains(t, schemas, "validatortest.ExternalMsg")
+
+	resp, err = validatorplugin.GenerateOpenAPI(req, validatorplugin.Options{OpenAPIFormat: "yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, "validator_proto3_oneof.openapi.yaml", resp.File[0].GetName())
+	assert.Contains(t, resp.File[0].GetContent(), "openapi: 3.1.0\n")
+	assert.Contains(t, resp.File[0].GetContent(), "exclusiveMinimum: 20\n")
+
+	existing := t.TempDir() + "/api.json"
+	assert.NoError(t, os.WriteFile(existing, []byte(`{
+  "openapi": "3.1.0",
+  "info": {"title": "api", "version": "1"},
+  "paths": {"/messages": {"get": {"responses": {"200": {"description": "ok"}}}}},
+  "components": {"schemas": {"validatortest.ExternalMsg": {
+    "type": "object",
+    "description": "Documented elsewhere",
+    "properties": {"Identifier": {"type": "string", "description": "An identifier"}},
+    "required": ["SomeValue"]
+  }}}
+}`), 0o600))
+	resp, err = validatorplugin.GenerateOpenAPI(req, validatorplugin.Options{OpenAPIMerge: existing})
+	assert.NoError(t, err)
+	doc = generatedJSON(t, resp, "api.json")
+	assert.Contains(t, doc["paths"], "/messages")
+	schemas = doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Contains(t, schemas, "validatortest.OneOfMessage3")
+	message = schemas["validatortest.ExternalMsg"].(map[string]interface{})
+	assert.Equal(t, "Documented elsewhere", message["description"])
+	assert.Equal(t, []interface{}{"SomeValue"}, message["required"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "description": "An identifier", "pattern": "^[a-z]{2,5}$"}, message["properties"].(map[string]interface{})["Identifier"])
+
+	existing = t.TempDir() + "/api.yaml"
+	assert.NoError(t, os.WriteFile(existing, []byte("openapi: 3.1.0\ninfo:\n  title: api\n  version: \"1\"\n"), 0o600))
+	resp, err = validatorplugin.GenerateOpenAPI(req, validatorplugin.Options{OpenAPIMerge: existing})
+	assert.NoError(t, err)
+	assert.Contains(t, resp.File[0].GetContent(), "    validatortest.OneOfMessage3:\n")
+}
+
+func TestZod(t *testing.T) {
+	resp, err := validatorplugin.GenerateZod(generatorRequest(t, "", File_validator_proto3_proto, File_validator_proto3_oneof_proto), validatorplugin.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, resp.File, 2)
+	assert.Equal(t, "validator_proto3.zod.ts", resp.File[0].GetName())
+	content := resp.File[0].GetContent()
+	assert.Contains(t, content, "import { z } from \"zod\";\n")
+	assert.Contains(t, content, `"SomeStringUnescaped": z.string().regex(/[\p{L}\p{N}](\{\p{L}\p{N}_- \]{0,28}[\p{L}\p{N}])?./u).default(""),`)
+	assert.Contains(t, content, `"someEmbeddedExists": z.lazy(() => ValidatorMessage3_EmbeddedMessageSchema),`)
+	assert.Contains(t, content, `"CustomErrorInt": z.number().int().lt(10, { message: "My Custom Error" }).default(0),`)
+	assert.Contains(t, content, `"SomeValue": z.union([z.number().int(), z.string().regex(/^-?[0-9]+$/)]).refine((v) => BigInt(v) > 0n, { message: "must be greater than 0" })`)
+	assert.Contains(t, content, `"someEnum": z.enum(["alpha3", "beta3"]).default("alpha3"),`)
+	assert.Contains(t, content, "export type ValidatorMessage3 = z.infer<typeof ValidatorMessage3Schema>;\n")
+	content = resp.File[1].GetContent()
+	assert.Contains(t, content, `"fiveRegex": z.string().regex(/^[a-z]{2,5}$/u).optional(),`)
+	assert.Contains(t, content, `.refine((v) => [v["threeInt"] !== undefined, v["fourInt"] !== undefined, v["fiveRegex"] !== undefined].filter(Boolean).length === 1, { message: "one of the fields of something must be set" })`)
+
+	_, err = validatorplugin.GenerateZod(generatorRequest(t, "", File_validator_proto3_cel_proto), validatorplugin.Options{})
+	assert.EqualError(t, err, "field validatortest.CelMessage3.max_replicas: validator.cel cannot be expressed with Zod")
+}
+
+func TestDoc(t *testing.T) {
+	resp, err := validatorplugin.GenerateDoc(generatorRequest(t, "", File_validator_proto3_proto, File_validator_proto3_message_proto), validatorplugin.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "validator_proto3.validators.md", resp.File[0].GetName())
+	content := resp.File[0].GetContent()
+	assert.Contains(t, content, "\n## validatortest.ValidatorMessage3\n")
+	assert.Contains(t, content, "| `SomeInt` | `uint32` | must be greater than '10' |  |  |\n")
+	assert.Contains(t, content, "| `CustomErrorInt` | `int32` | must be less than '10' | My Custom Error |  |\n")
+	assert.Contains(t, content, "| `SomeIntRep` | `repeated uint32` | each element must be greater than '10' |  |  |\n")
+	content = resp.File[1].GetContent()
+	assert.Contains(t, content, "| `Name` | `string` | must be set |  |  |\n")
+	assert.Contains(t, content, "- one of the fields Email, Phone must be set\n")
+
+	resp, err = validatorplugin.GenerateDoc(generatorRequest(t, "", File_validator_proto3_cel_proto), validatorplugin.Options{DocFormat: "html"})
+	assert.NoError(t, err)
+	assert.Equal(t, "validator_proto3_cel.validators.html", resp.File[0].GetName())
+	assert.Contains(t, resp.File[0].GetContent(), "<td>must satisfy the expression &#39;this &lt;= 10u&#39;: must be at most 10</td>")
+}
+
+func TestSQL(t *testing.T) {
+	resp, err := validatorplugin.GenerateSQL(generatorRequest(t, "", File_validator_proto3_proto, File_validator_proto3_oneof_proto, File_validator_proto3_message_proto), validatorplugin.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "validator_proto3.validators.sql", resp.File[0].GetName())
+	content := resp.File[0].GetContent()
+	assert.Contains(t, content, `ALTER TABLE "validator_message3" ADD CONSTRAINT "validator_message3_some_int_check" CHECK ("some_int" > 10);`)
+	assert.Contains(t, content, `ALTER TABLE "validator_message3" ADD CONSTRAINT "validator_message3_some_double_check" CHECK ("some_double" >= 0.25 AND "some_double" <= 0.75);`)
+	assert.Contains(t, content, `ALTER TABLE "validator_message3" ADD CONSTRAINT "validator_message3_some_string_eq_req_check" CHECK (octet_length("some_string_eq_req") = 10);`)
+	assert.Contains(t, content, `ALTER TABLE "validator_message3" ADD CONSTRAINT "validator_message3_repeated_check" CHECK (cardinality("repeated") >= 2 AND cardinality("repeated") <= 5);`)
+	assert.Contains(t, content, `ALTER TABLE "validator_message3" ALTER COLUMN "some_embedded_exists" SET NOT NULL;`)
+	assert.Contains(t, content, `ALTER TABLE "validator_message3_embedded_message" ADD CONSTRAINT "validator_message3_embedded_message_identifier_check" CHECK ("identifier" ~ '^[a-z]{2,5}$');`)
+	assert.Contains(t, content, `CHECK ("some_enum" IN (0, 1));`)
+	assert.Contains(t, resp.File[1].GetContent(), `ALTER TABLE "one_of_message3" ADD CONSTRAINT "one_of_message3_something_check" CHECK (num_nonnulls("three_int", "four_int", "five_regex") = 1);`)
+	content = resp.File[2].GetContent()
+	assert.Contains(t, content, `ALTER TABLE "message_rules3" ALTER COLUMN "name" SET NOT NULL;`)
+	assert.Contains(t, content, `ALTER TABLE "message_rules3" ADD CONSTRAINT "message_rules3_contact_check" CHECK (num_nonnulls("email", "phone") >= 1);`)
+	assert.NotContains(t, content, "disabled_message3")
+
+	resp, err = validatorplugin.GenerateSQL(generatorRequest(t, "", File_validator_proto3_proto), validatorplugin.Options{SQLNaming: "json", SQLEnums: "name"})
+	assert.NoError(t, err)
+	assert.Contains(t, resp.File[0].GetContent(), `ALTER TABLE "validator_message3" ADD CONSTRAINT "validator_message3_someEnum_check" CHECK ("someEnum" IN ('alpha3', 'beta3'));`)
+
+	_, err = validatorplugin.GenerateSQL(generatorRequest(t, "", File_validator_proto3_proto), validatorplugin.Options{SQLNaming: "kebab"})
+	assert.EqualError(t, err, "unknown SQL naming 'kebab'")
+}
+
+// jsonRequest returns a POST request with a JSON body.
+func jsonRequest(target, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return req
+}
+
+func TestHTTPHandler(t *testing.T) {
+	var handled *ItemsMessage3
+	handler := validator.HTTPHandler(func() interface{} { return &ItemsMessage3{} }, func(w http.ResponseWriter, r *http.Request, msg interface{}) {
+		handled = msg.(*ItemsMessage3)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	problem := func(rec *httptest.ResponseRecorder) *validator.Problem {
+		assert.Equal(t, validator.ProblemContentType, rec.Header().Get("Content-Type"))
+		p := &validator.Problem{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), p))
+		return p
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest("/items", `{"Names": ["ab"], "Digests": ["AAAAAA=="]}`))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"ab"}, handled.Names)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, jsonRequest("/items", `{"Names": ["ab", "A1"], "Digests": ["AAAAAA=="]}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	p := problem(rec)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, []validator.ProblemError{{Field: "Names[1]", Pointer: "#/Names/1", Violation: "regex", Detail: "value 'A1' must be a string conforming to regex \"^[a-z]+$\""}}, p.Errors)
+
+	rec = httptest.NewRecorder()
+	unique := &UniqueMessage3{Items: []*KeyedItem{{Id: "a"}, {Id: "a"}}}
+	validator.WriteProblem(rec, unique, unique.ValidateAll())
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []validator.ProblemError{{Field: "Items[1]", Pointer: "#/Items/1", Violation: "unique", Detail: "value 'a' must have a unique 'Id', found duplicates at indexes '0' and '1'"}}, problem(rec).Errors)
+
+	observer := &validator.MemoryObserver{}
+	var msg interface{}
+	middleware := validator.HTTPMiddleware(func() interface{} { return &CelMessage3{} })
+	server := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg = validator.HTTPMessage(r)
+	}))
+	rec = httptest.NewRecorder()
+	req := jsonRequest("/cel", `{"minReplicas": 1, "maxReplicas": 11}`)
+	server.ServeHTTP(rec, req.WithContext(validator.WithObserver(req.Context(), observer)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, problem(rec).Errors, validator.ProblemError{Field: "maxReplicas", Pointer: "#/maxReplicas", Violation: "max_replicas", Detail: "must be at most 10"})
+	assert.Nil(t, msg)
+	assert.Equal(t, 1, observer.Validations("validatortest.CelMessage3"))
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, jsonRequest("/cel", `{"startTime": "2024-01-01T00:00:00Z", "endTime": "2024-01-02T00:00:00Z", "minReplicas": 1, "maxReplicas": 3}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint32(3), msg.(*CelMessage3).MaxReplicas)
+}
+
+func TestLocate(t *testing.T) {
+	unique := &UniqueMessage3{Items: []*KeyedItem{{Id: "a"}, {Id: "a"}}}
+	data := []byte(`{
+  "Tags": [],
+  "Items": [
+    {"Id": "a"},
+    {"Id": "a", "Value": 1}
+  ]
+}`)
+	located, err := validator.LocateJSON(data, unique, unique.ValidateAll())
+	assert.NoError(t, err)
+	if !assert.Len(t, located, 1) {
+		return
+	}
+	assert.Equal(t, "Items[1]", located[0].Path)
+	assert.Equal(t, 5, located[0].Line)
+	assert.Equal(t, 5, located[0].Column)
+	assert.Equal(t, "unique", located[0].Violation)
+	assert.True(t, strings.HasPrefix(located[0].String(), "5:5: "), located[0].String())
+
+	text := []byte(`# duplicated items
+Items { Id: "a" }
+Items <
+  Id: 'a'
+  Value: 1
+>
+`)
+	located, err = validator.LocateText(text, unique, unique.ValidateAll())
+	assert.NoError(t, err)
+	if !assert.Len(t, located, 1) {
+		return
+	}
+	assert.Equal(t, "Items[1]", located[0].Path)
+	assert.Equal(t, 3, located[0].Line)
+	assert.Equal(t, 7, located[0].Column)
+
+	cel := &CelMessage3{MinReplicas: 1, MaxReplicas: 11}
+	located, err = validator.LocateText([]byte("min_replicas: 1\n  max_replicas: 11 # too many"), cel, cel.ValidateAll())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, located)
+	for _, v := range located {
+		if v.Violation == "max_replicas" {
+			assert.Equal(t, "max_replicas", v.Path)
+			assert.Equal(t, 2, v.Line)
+			assert.Equal(t, 3, v.Column)
+		}
+	}
+
+	// unset fields are located at their enclosing message
+	items := &ItemsMessage3{Names: []string{"A1"}}
+	located, err = validator.LocateJSON([]byte("\n  {\"Names\": [\"A1\"]}"), items, items.ValidateAll())
+	assert.NoError(t, err)
+	for _, v := range located {
+		switch v.Path {
+		case "Names[0]":
+			assert.Equal(t, []int{2, 14}, []int{v.Line, v.Column})
+		case "Digests":
+			assert.Equal(t, []int{2, 3}, []int{v.Line, v.Column})
+		default:
+			t.Errorf("unexpected violation %s", v)
+		}
+	}
+
+	_, err = validator.LocateJSON([]byte(`{"Names": [`), items, items.ValidateAll())
+	assert.Error(t, err)
+	_, err = validator.LocateJSON(data, unique, errors.New("not a validation error"))
+	assert.Error(t, err)
+	located, err = validator.LocateJSON(data, unique, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, located)
+}
@@ -19,6 +19,127 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Severity int32
+
+const (
+	Severity_SEVERITY_ERROR   Severity = 0
+	Severity_SEVERITY_WARNING Severity = 1
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_ERROR",
+		1: "SEVERITY_WARNING",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_ERROR":   0,
+		"SEVERITY_WARNING": 1,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_validator_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_validator_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Severity) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Severity(num)
+	return nil
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{0}
+}
+
+type Monotonic int32
+
+const (
+	Monotonic_MONOTONIC_UNSPECIFIED         Monotonic = 0
+	Monotonic_MONOTONIC_INCREASING          Monotonic = 1
+	Monotonic_MONOTONIC_STRICTLY_INCREASING Monotonic = 2
+	Monotonic_MONOTONIC_DECREASING          Monotonic = 3
+	Monotonic_MONOTONIC_STRICTLY_DECREASING Monotonic = 4
+)
+
+// Enum value maps for Monotonic.
+var (
+	Monotonic_name = map[int32]string{
+		0: "MONOTONIC_UNSPECIFIED",
+		1: "MONOTONIC_INCREASING",
+		2: "MONOTONIC_STRICTLY_INCREASING",
+		3: "MONOTONIC_DECREASING",
+		4: "MONOTONIC_STRICTLY_DECREASING",
+	}
+	Monotonic_value = map[string]int32{
+		"MONOTONIC_UNSPECIFIED":         0,
+		"MONOTONIC_INCREASING":          1,
+		"MONOTONIC_STRICTLY_INCREASING": 2,
+		"MONOTONIC_DECREASING":          3,
+		"MONOTONIC_STRICTLY_DECREASING": 4,
+	}
+)
+
+func (x Monotonic) Enum() *Monotonic {
+	p := new(Monotonic)
+	*p = x
+	return p
+}
+
+func (x Monotonic) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Monotonic) Descriptor() protoreflect.EnumDescriptor {
+	return file_validator_proto_enumTypes[1].Descriptor()
+}
+
+func (Monotonic) Type() protoreflect.EnumType {
+	return &file_validator_proto_enumTypes[1]
+}
+
+func (x Monotonic) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Monotonic) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Monotonic(num)
+	return nil
+}
+
+// Deprecated: Use Monotonic.Descriptor instead.
+func (Monotonic) EnumDescriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{1}
+}
+
 type FieldValidator struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -74,6 +195,48 @@ type FieldValidator struct {
 	UuidVer *int32 `protobuf:"varint,18,opt,name=uuid_ver,json=uuidVer" json:"uuid_ver,omitempty"`
 	// Require that the field is set.
 	Required *bool `protobuf:"varint,19,opt,name=required" json:"required,omitempty"`
+	// Used for google.protobuf.Any fields, requires the type URL of the packed message to be one of these values.
+	// Entries are either full type URLs or fully-qualified message names, in which case the
+	// "type.googleapis.com/" prefix is assumed.
+	AnyIn []string `protobuf:"bytes,20,rep,name=any_in,json=anyIn" json:"any_in,omitempty"`
+	// Used for google.protobuf.Any fields, requires the type URL of the packed message to be none of these values.
+	// Entries follow the same format as any_in.
+	AnyNotIn []string `protobuf:"bytes,21,rep,name=any_not_in,json=anyNotIn" json:"any_not_in,omitempty"`
+	// Used for google.protobuf.Any fields, resolves the packed message type at runtime, unmarshals it and calls its
+	// validators. Types that cannot be resolved are reported as violations.
+	AnyValidate *bool `protobuf:"varint,22,opt,name=any_validate,json=anyValidate" json:"any_validate,omitempty"`
+	// Used for repeated scalar and enum fields, requires all elements to be distinct.
+	Unique *bool `protobuf:"varint,23,opt,name=unique" json:"unique,omitempty"`
+	// Used for repeated message fields, requires the named scalar sub-field to be distinct across all elements. Missing
+	// elements and elements not setting the sub-field are ignored.
+	UniqueBy *string `protobuf:"bytes,24,opt,name=unique_by,json=uniqueBy" json:"unique_by,omitempty"`
+	// Used for repeated fields, rules applied to each element of the field. Rules set directly on a repeated field
+	// apply to the field as a whole: element counts, uniqueness and length_* which bound the number of elements.
+	Items *FieldValidator `protobuf:"bytes,25,opt,name=items" json:"items,omitempty"`
+	// CEL expressions the field must satisfy, `this` is the value of the field (the whole list for repeated fields).
+	Cel []*Constraint `protobuf:"bytes,26,rep,name=cel" json:"cel,omitempty"`
+	// Requires the field to be set when the condition on a sibling field holds.
+	RequiredIf *FieldCondition `protobuf:"bytes,27,opt,name=required_if,json=requiredIf" json:"required_if,omitempty"`
+	// Requires the field to be set unless the condition on a sibling field holds.
+	RequiredUnless *FieldCondition `protobuf:"bytes,28,opt,name=required_unless,json=requiredUnless" json:"required_unless,omitempty"`
+	// Forbids the field to be set when the condition on a sibling field holds.
+	ForbiddenIf *FieldCondition `protobuf:"bytes,29,opt,name=forbidden_if,json=forbiddenIf" json:"forbidden_if,omitempty"`
+	// Validation groups the rules of this validator belong to, the "default" group if not set.
+	Groups []string `protobuf:"bytes,30,rep,name=groups" json:"groups,omitempty"`
+	// Additional rules of the field, usually tagged with other validation groups than the rules above.
+	GroupRules []*FieldValidator `protobuf:"bytes,31,rep,name=group_rules,json=groupRules" json:"group_rules,omitempty"`
+	// Requires the paths of a google.protobuf.FieldMask field to be field paths of the given message type, written with
+	// its full name (e.g. "mypackage.User").
+	FieldMaskTarget *string `protobuf:"bytes,32,opt,name=field_mask_target,json=fieldMaskTarget" json:"field_mask_target,omitempty"`
+	// Requires the field to keep its previous value in ValidateTransition.
+	Immutable *bool `protobuf:"varint,33,opt,name=immutable" json:"immutable,omitempty"`
+	// Requires the field to only move in one direction in ValidateTransition. Only numeric fields are supported.
+	Monotonic *Monotonic `protobuf:"varint,34,opt,name=monotonic,enum=validator.Monotonic" json:"monotonic,omitempty"`
+	// Allowed changes of an enum field in ValidateTransition, any other change is a violation.
+	Transitions []*EnumTransition `protobuf:"bytes,35,rep,name=transitions" json:"transitions,omitempty"`
+	// Severity of the violations of the rules of this validator, SEVERITY_WARNING violations don't fail ValidateAll and
+	// are skipped by Validate.
+	Severity *Severity `protobuf:"varint,36,opt,name=severity,enum=validator.Severity" json:"severity,omitempty"`
 }
 
 func (x *FieldValidator) Reset() {
@@ -241,6 +404,182 @@ func (x *FieldValidator) GetRequired() bool {
 	return false
 }
 
+func (x *FieldValidator) GetAnyIn() []string {
+	if x != nil {
+		return x.AnyIn
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetAnyNotIn() []string {
+	if x != nil {
+		return x.AnyNotIn
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetAnyValidate() bool {
+	if x != nil && x.AnyValidate != nil {
+		return *x.AnyValidate
+	}
+	return false
+}
+
+func (x *FieldValidator) GetUnique() bool {
+	if x != nil && x.Unique != nil {
+		return *x.Unique
+	}
+	return false
+}
+
+func (x *FieldValidator) GetUniqueBy() string {
+	if x != nil && x.UniqueBy != nil {
+		return *x.UniqueBy
+	}
+	return ""
+}
+
+func (x *FieldValidator) GetItems() *FieldValidator {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetCel() []*Constraint {
+	if x != nil {
+		return x.Cel
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetRequiredIf() *FieldCondition {
+	if x != nil {
+		return x.RequiredIf
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetRequiredUnless() *FieldCondition {
+	if x != nil {
+		return x.RequiredUnless
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetForbiddenIf() *FieldCondition {
+	if x != nil {
+		return x.ForbiddenIf
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetGroupRules() []*FieldValidator {
+	if x != nil {
+		return x.GroupRules
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetFieldMaskTarget() string {
+	if x != nil && x.FieldMaskTarget != nil {
+		return *x.FieldMaskTarget
+	}
+	return ""
+}
+
+func (x *FieldValidator) GetImmutable() bool {
+	if x != nil && x.Immutable != nil {
+		return *x.Immutable
+	}
+	return false
+}
+
+func (x *FieldValidator) GetMonotonic() Monotonic {
+	if x != nil && x.Monotonic != nil {
+		return *x.Monotonic
+	}
+	return Monotonic_MONOTONIC_UNSPECIFIED
+}
+
+func (x *FieldValidator) GetTransitions() []*EnumTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+func (x *FieldValidator) GetSeverity() Severity {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return Severity_SEVERITY_ERROR
+}
+
+type EnumTransition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Enum value name or number the field changes from.
+	From *string `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	// Enum value names or numbers the field can change to.
+	To []string `protobuf:"bytes,2,rep,name=to" json:"to,omitempty"`
+}
+
+func (x *EnumTransition) Reset() {
+	*x = EnumTransition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validator_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumTransition) ProtoMessage() {}
+
+func (x *EnumTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumTransition.ProtoReflect.Descriptor instead.
+func (*EnumTransition) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EnumTransition) GetFrom() string {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return ""
+}
+
+func (x *EnumTransition) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
 type OneofValidator struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -253,7 +592,7 @@ type OneofValidator struct {
 func (x *OneofValidator) Reset() {
 	*x = OneofValidator{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_validator_proto_msgTypes[1]
+		mi := &file_validator_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -266,7 +605,7 @@ func (x *OneofValidator) String() string {
 func (*OneofValidator) ProtoMessage() {}
 
 func (x *OneofValidator) ProtoReflect() protoreflect.Message {
-	mi := &file_validator_proto_msgTypes[1]
+	mi := &file_validator_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -279,7 +618,7 @@ func (x *OneofValidator) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OneofValidator.ProtoReflect.Descriptor instead.
 func (*OneofValidator) Descriptor() ([]byte, []int) {
-	return file_validator_proto_rawDescGZIP(), []int{1}
+	return file_validator_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *OneofValidator) GetRequired() bool {
@@ -289,6 +628,300 @@ func (x *OneofValidator) GetRequired() bool {
 	return false
 }
 
+type MessageValidator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Skip the generation of the Validate and ValidateAll methods for this message.
+	Disabled *bool `protobuf:"varint,1,opt,name=disabled" json:"disabled,omitempty"`
+	// Skip the validation of this message when it is the field of another message. The message itself keeps its
+	// Validate and ValidateAll methods.
+	SkipNested *bool `protobuf:"varint,2,opt,name=skip_nested,json=skipNested" json:"skip_nested,omitempty"`
+	// Names of the fields that must be set.
+	Required []string `protobuf:"bytes,3,rep,name=required" json:"required,omitempty"`
+	// Groups of fields of which at least one must be set.
+	AtLeastOneOf []*FieldGroup `protobuf:"bytes,4,rep,name=at_least_one_of,json=atLeastOneOf" json:"at_least_one_of,omitempty"`
+	// Groups of fields of which at most one can be set.
+	AtMostOneOf []*FieldGroup `protobuf:"bytes,5,rep,name=at_most_one_of,json=atMostOneOf" json:"at_most_one_of,omitempty"`
+	// CEL expressions the message must satisfy, `this` is the message.
+	Cel []*Constraint `protobuf:"bytes,6,rep,name=cel" json:"cel,omitempty"`
+}
+
+func (x *MessageValidator) Reset() {
+	*x = MessageValidator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageValidator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageValidator) ProtoMessage() {}
+
+func (x *MessageValidator) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageValidator.ProtoReflect.Descriptor instead.
+func (*MessageValidator) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MessageValidator) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *MessageValidator) GetSkipNested() bool {
+	if x != nil && x.SkipNested != nil {
+		return *x.SkipNested
+	}
+	return false
+}
+
+func (x *MessageValidator) GetRequired() []string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+func (x *MessageValidator) GetAtLeastOneOf() []*FieldGroup {
+	if x != nil {
+		return x.AtLeastOneOf
+	}
+	return nil
+}
+
+func (x *MessageValidator) GetAtMostOneOf() []*FieldGroup {
+	if x != nil {
+		return x.AtMostOneOf
+	}
+	return nil
+}
+
+func (x *MessageValidator) GetCel() []*Constraint {
+	if x != nil {
+		return x.Cel
+	}
+	return nil
+}
+
+type FieldGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the fields of the group. Fields that are part of a oneof are not allowed.
+	Fields []string `protobuf:"bytes,1,rep,name=fields" json:"fields,omitempty"`
+	// Name the violations of the group are reported under, the first field of the group if not set.
+	Name *string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (x *FieldGroup) Reset() {
+	*x = FieldGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldGroup) ProtoMessage() {}
+
+func (x *FieldGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldGroup.ProtoReflect.Descriptor instead.
+func (*FieldGroup) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FieldGroup) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *FieldGroup) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type FieldCondition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the sibling field the condition is about.
+	Field *string `protobuf:"bytes,1,opt,name=field" json:"field,omitempty"`
+	// Values of the sibling field for which the condition holds: enum value names or numbers, strings, numbers or
+	// booleans. The condition holds whenever the sibling field is set if no value is given.
+	In []string `protobuf:"bytes,2,rep,name=in" json:"in,omitempty"`
+}
+
+func (x *FieldCondition) Reset() {
+	*x = FieldCondition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldCondition) ProtoMessage() {}
+
+func (x *FieldCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldCondition.ProtoReflect.Descriptor instead.
+func (*FieldCondition) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FieldCondition) GetField() string {
+	if x != nil && x.Field != nil {
+		return *x.Field
+	}
+	return ""
+}
+
+func (x *FieldCondition) GetIn() []string {
+	if x != nil {
+		return x.In
+	}
+	return nil
+}
+
+type Constraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Identifier of the violation reported when the expression does not hold, "cel" if not set.
+	Id *string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// Message of the violation reported when the expression does not hold.
+	Message *string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	// CEL expression evaluating to a bool, checked against the message descriptor at generation time.
+	Expression *string `protobuf:"bytes,3,opt,name=expression" json:"expression,omitempty"`
+	// Validation groups the expression belongs to. Field expressions default to the groups of their validator, message
+	// expressions to the "default" group.
+	Groups []string `protobuf:"bytes,4,rep,name=groups" json:"groups,omitempty"`
+	// Severity of the violations of the expression. Field expressions default to the severity of their validator.
+	Severity *Severity `protobuf:"varint,5,opt,name=severity,enum=validator.Severity" json:"severity,omitempty"`
+}
+
+func (x *Constraint) Reset() {
+	*x = Constraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Constraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Constraint) ProtoMessage() {}
+
+func (x *Constraint) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Constraint.ProtoReflect.Descriptor instead.
+func (*Constraint) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Constraint) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *Constraint) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *Constraint) GetExpression() string {
+	if x != nil && x.Expression != nil {
+		return *x.Expression
+	}
+	return ""
+}
+
+func (x *Constraint) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *Constraint) GetSeverity() Severity {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return Severity_SEVERITY_ERROR
+}
+
 var Gogo_E_Field = &proto.ExtensionDesc{
 	ExtendedType:  (*descriptor.FieldOptions)(nil),
 	ExtensionType: (*FieldValidator)(nil),
@@ -307,6 +940,15 @@ var Gogo_E_Oneof = &proto.ExtensionDesc{
 	Filename:      "validator.proto",
 }
 
+var Gogo_E_Message = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.MessageOptions)(nil),
+	ExtensionType: (*MessageValidator)(nil),
+	Field:         65032,
+	Name:          "validator.message",
+	Tag:           "bytes,65032,opt,name=message",
+	Filename:      "validator.proto",
+}
+
 var file_validator_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
@@ -324,6 +966,14 @@ var file_validator_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,65031,opt,name=oneof",
 		Filename:      "validator.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*MessageValidator)(nil),
+		Field:         65032,
+		Name:          "validator.message",
+		Tag:           "bytes,65032,opt,name=message",
+		Filename:      "validator.proto",
+	},
 }
 
 // Extension fields to descriptorpb.FieldOptions.
@@ -338,14 +988,20 @@ var (
 	E_Oneof = &file_validator_proto_extTypes[1]
 )
 
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional validator.MessageValidator message = 65032;
+	E_Message = &file_validator_proto_extTypes[2]
+)
+
 var File_validator_proto protoreflect.FileDescriptor
 
 var file_validator_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x1a, 0x20, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdb,
-	0x04, 0x0a, 0x0e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc0,
+	0x0a, 0x0a, 0x0e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f,
 	0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x6e, 0x74, 0x5f, 0x67,
 	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x74, 0x47, 0x74, 0x12, 0x15,
@@ -382,24 +1038,127 @@ var file_validator_proto_rawDesc = []byte{
 	0x6e, 0x45, 0x6e, 0x75, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x75, 0x69, 0x64, 0x5f, 0x76, 0x65,
 	0x72, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x75, 0x75, 0x69, 0x64, 0x56, 0x65, 0x72,
 	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x2c, 0x0a, 0x0e,
-	0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1a,
-	0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x3a, 0x50, 0x0a, 0x05, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x86, 0xfc, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c,
-	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x69,
-	0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x50, 0x0a, 0x05,
-	0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x4f, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x87, 0xfc, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x56, 0x61,
-	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x05, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x42, 0x33,
-	0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6f, 0x6e,
-	0x73, 0x74, 0x72, 0x75, 0x6d, 0x2f, 0x67, 0x6f, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2d, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x3b, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
-	0x74, 0x6f, 0x72,
+	0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x15, 0x0a, 0x06,
+	0x61, 0x6e, 0x79, 0x5f, 0x69, 0x6e, 0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6e,
+	0x79, 0x49, 0x6e, 0x12, 0x1c, 0x0a, 0x0a, 0x61, 0x6e, 0x79, 0x5f, 0x6e, 0x6f, 0x74, 0x5f, 0x69,
+	0x6e, 0x18, 0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x74, 0x49,
+	0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6e, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x6e, 0x79, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x62, 0x79, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x42, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x6f, 0x72, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x27, 0x0a, 0x03, 0x63, 0x65,
+	0x6c, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x52, 0x03,
+	0x63, 0x65, 0x6c, 0x12, 0x3a, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f,
+	0x69, 0x66, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x49, 0x66, 0x12,
+	0x42, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x6c, 0x65,
+	0x73, 0x73, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x55, 0x6e, 0x6c,
+	0x65, 0x73, 0x73, 0x12, 0x3c, 0x0a, 0x0c, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e,
+	0x5f, 0x69, 0x66, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x49,
+	0x66, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x1e, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x3a, 0x0a, 0x0b, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d,
+	0x61, 0x73, 0x6b, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x20, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6d, 0x6d, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x21,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x6d, 0x6d, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12,
+	0x32, 0x0a, 0x09, 0x6d, 0x6f, 0x6e, 0x6f, 0x74, 0x6f, 0x6e, 0x69, 0x63, 0x18, 0x22, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4d,
+	0x6f, 0x6e, 0x6f, 0x74, 0x6f, 0x6e, 0x69, 0x63, 0x52, 0x09, 0x6d, 0x6f, 0x6e, 0x6f, 0x74, 0x6f,
+	0x6e, 0x69, 0x63, 0x12, 0x3b, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x23, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x2f, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x24, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53,
+	0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74,
+	0x79, 0x22, 0x34, 0x0a, 0x0e, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x2c, 0x0a, 0x0e, 0x4f, 0x6e, 0x65, 0x6f, 0x66,
+	0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x8e, 0x02, 0x0a, 0x10, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69,
+	0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69,
+	0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x6e,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x6b, 0x69,
+	0x70, 0x4e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x0f, 0x61, 0x74, 0x5f, 0x6c, 0x65, 0x61, 0x73, 0x74, 0x5f,
+	0x6f, 0x6e, 0x65, 0x5f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x52, 0x0c, 0x61, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x74, 0x4f, 0x6e, 0x65, 0x4f,
+	0x66, 0x12, 0x3a, 0x0a, 0x0e, 0x61, 0x74, 0x5f, 0x6d, 0x6f, 0x73, 0x74, 0x5f, 0x6f, 0x6e, 0x65,
+	0x5f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x0b, 0x61, 0x74, 0x4d, 0x6f, 0x73, 0x74, 0x4f, 0x6e, 0x65, 0x4f, 0x66, 0x12, 0x27, 0x0a,
+	0x03, 0x63, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x52, 0x03, 0x63, 0x65, 0x6c, 0x22, 0x38, 0x0a, 0x0a, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x36, 0x0a, 0x0e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x69, 0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x0a, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x73, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
+	0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x2a, 0x34, 0x0a, 0x08, 0x53, 0x65,
+	0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49,
+	0x54, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45,
+	0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41, 0x52, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x2a, 0xa0, 0x01, 0x0a, 0x09, 0x4d, 0x6f, 0x6e, 0x6f, 0x74, 0x6f, 0x6e, 0x69, 0x63, 0x12, 0x19,
+	0x0a, 0x15, 0x4d, 0x4f, 0x4e, 0x4f, 0x54, 0x4f, 0x4e, 0x49, 0x43, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x4d, 0x4f, 0x4e,
+	0x4f, 0x54, 0x4f, 0x4e, 0x49, 0x43, 0x5f, 0x49, 0x4e, 0x43, 0x52, 0x45, 0x41, 0x53, 0x49, 0x4e,
+	0x47, 0x10, 0x01, 0x12, 0x21, 0x0a, 0x1d, 0x4d, 0x4f, 0x4e, 0x4f, 0x54, 0x4f, 0x4e, 0x49, 0x43,
+	0x5f, 0x53, 0x54, 0x52, 0x49, 0x43, 0x54, 0x4c, 0x59, 0x5f, 0x49, 0x4e, 0x43, 0x52, 0x45, 0x41,
+	0x53, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x4d, 0x4f, 0x4e, 0x4f, 0x54, 0x4f,
+	0x4e, 0x49, 0x43, 0x5f, 0x44, 0x45, 0x43, 0x52, 0x45, 0x41, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x03,
+	0x12, 0x21, 0x0a, 0x1d, 0x4d, 0x4f, 0x4e, 0x4f, 0x54, 0x4f, 0x4e, 0x49, 0x43, 0x5f, 0x53, 0x54,
+	0x52, 0x49, 0x43, 0x54, 0x4c, 0x59, 0x5f, 0x44, 0x45, 0x43, 0x52, 0x45, 0x41, 0x53, 0x49, 0x4e,
+	0x47, 0x10, 0x04, 0x3a, 0x50, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x86, 0xfc, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x50, 0x0a, 0x05, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x87, 0xfc,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
+	0x52, 0x05, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x3a, 0x58, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x88, 0xfc, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6d, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x6d, 0x2f, 0x67, 0x6f, 0x2d, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x3b, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
 }
 
 var (
@@ -414,31 +1173,63 @@ func file_validator_proto_rawDescGZIP() []byte {
 	return file_validator_proto_rawDescData
 }
 
-var file_validator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_validator_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_validator_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_validator_proto_goTypes = []interface{}{
-	(*FieldValidator)(nil),            // 0: validator.FieldValidator
-	(*OneofValidator)(nil),            // 1: validator.OneofValidator
-	(*descriptorpb.FieldOptions)(nil), // 2: google.protobuf.FieldOptions
-	(*descriptorpb.OneofOptions)(nil), // 3: google.protobuf.OneofOptions
+	(Severity)(0),                       // 0: validator.Severity
+	(Monotonic)(0),                      // 1: validator.Monotonic
+	(*FieldValidator)(nil),              // 2: validator.FieldValidator
+	(*EnumTransition)(nil),              // 3: validator.EnumTransition
+	(*OneofValidator)(nil),              // 4: validator.OneofValidator
+	(*MessageValidator)(nil),            // 5: validator.MessageValidator
+	(*FieldGroup)(nil),                  // 6: validator.FieldGroup
+	(*FieldCondition)(nil),              // 7: validator.FieldCondition
+	(*Constraint)(nil),                  // 8: validator.Constraint
+	(*descriptorpb.FieldOptions)(nil),   // 9: google.protobuf.FieldOptions
+	(*descriptorpb.OneofOptions)(nil),   // 10: google.protobuf.OneofOptions
+	(*descriptorpb.MessageOptions)(nil), // 11: google.protobuf.MessageOptions
 }
 var file_validator_proto_depIdxs = []int32{
-	2, // 0: validator.field:extendee -> google.protobuf.FieldOptions
-	3, // 1: validator.oneof:extendee -> google.protobuf.OneofOptions
-	0, // 2: validator.field:type_name -> validator.FieldValidator
-	1, // 3: validator.oneof:type_name -> validator.OneofValidator
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	2, // [2:4] is the sub-list for extension type_name
-	0, // [0:2] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2,  // 0: validator.FieldValidator.items:type_name -> validator.FieldValidator
+	8,  // 1: validator.FieldValidator.cel:type_name -> validator.Constraint
+	7,  // 2: validator.FieldValidator.required_if:type_name -> validator.FieldCondition
+	7,  // 3: validator.FieldValidator.required_unless:type_name -> validator.FieldCondition
+	7,  // 4: validator.FieldValidator.forbidden_if:type_name -> validator.FieldCondition
+	2,  // 5: validator.FieldValidator.group_rules:type_name -> validator.FieldValidator
+	1,  // 6: validator.FieldValidator.monotonic:type_name -> validator.Monotonic
+	3,  // 7: validator.FieldValidator.transitions:type_name -> validator.EnumTransition
+	0,  // 8: validator.FieldValidator.severity:type_name -> validator.Severity
+	6,  // 9: validator.MessageValidator.at_least_one_of:type_name -> validator.FieldGroup
+	6,  // 10: validator.MessageValidator.at_most_one_of:type_name -> validator.FieldGroup
+	8,  // 11: validator.MessageValidator.cel:type_name -> validator.Constraint
+	0,  // 12: validator.Constraint.severity:type_name -> validator.Severity
+	9,  // 13: validator.field:extendee -> google.protobuf.FieldOptions
+	10, // 14: validator.oneof:extendee -> google.protobuf.OneofOptions
+	11, // 15: validator.message:extendee -> google.protobuf.MessageOptions
+	2,  // 16: validator.field:type_name -> validator.FieldValidator
+	4,  // 17: validator.oneof:type_name -> validator.OneofValidator
+	5,  // 18: validator.message:type_name -> validator.MessageValidator
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	16, // [16:19] is the sub-list for extension type_name
+	13, // [13:16] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() {
 	file_validator_proto_init()
 	proto.RegisterType((*FieldValidator)(nil), "validator.FieldValidator")
+	proto.RegisterType((*EnumTransition)(nil), "validator.EnumTransition")
 	proto.RegisterType((*OneofValidator)(nil), "validator.OneofValidator")
+	proto.RegisterType((*MessageValidator)(nil), "validator.MessageValidator")
+	proto.RegisterType((*FieldGroup)(nil), "validator.FieldGroup")
+	proto.RegisterType((*FieldCondition)(nil), "validator.FieldCondition")
+	proto.RegisterType((*Constraint)(nil), "validator.Constraint")
+	proto.RegisterEnum("validator.Severity", Severity_name, Severity_value)
+	proto.RegisterEnum("validator.Monotonic", Monotonic_name, Monotonic_value)
 	proto.RegisterExtension(Gogo_E_Field)
 	proto.RegisterExtension(Gogo_E_Oneof)
+	proto.RegisterExtension(Gogo_E_Message)
 }
 func file_validator_proto_init() {
 	if File_validator_proto != nil {
@@ -458,6 +1249,18 @@ func file_validator_proto_init() {
 			}
 		}
 		file_validator_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumTransition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validator_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*OneofValidator); i {
 			case 0:
 				return &v.state
@@ -469,19 +1272,68 @@ func file_validator_proto_init() {
 				return nil
 			}
 		}
+		file_validator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageValidator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validator_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validator_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldCondition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_validator_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Constraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_validator_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
-			NumExtensions: 2,
+			NumEnums:      2,
+			NumMessages:   7,
+			NumExtensions: 3,
 			NumServices:   0,
 		},
 		GoTypes:           file_validator_proto_goTypes,
 		DependencyIndexes: file_validator_proto_depIdxs,
+		EnumInfos:         file_validator_proto_enumTypes,
 		MessageInfos:      file_validator_proto_msgTypes,
 		ExtensionInfos:    file_validator_proto_extTypes,
 	}.Build()
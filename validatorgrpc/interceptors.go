@@ -0,0 +1,60 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package validatorgrpc validates the requests of gRPC servers with the generated validators.
+package validatorgrpc
+
+import (
+	"context"
+	"errors"
+
+	validator "github.com/monstrum/go-proto-validators"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a server interceptor validating the requests with validator.ValidateContext and
+// opts, the Observer of the request context being notified. Invalid requests fail with codes.InvalidArgument without
+// reaching the handler.
+func UnaryServerInterceptor(opts ...validator.Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validate(ctx, req, opts); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor validating each message received from the client streams like
+// UnaryServerInterceptor. RecvMsg fails with codes.InvalidArgument for invalid messages.
+func StreamServerInterceptor(opts ...validator.Option) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss, opts: opts})
+	}
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+	opts []validator.Option
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validate(s.Context(), m, s.opts)
+}
+
+// validate validates msg, converting the error to a gRPC status: codes.InvalidArgument for violations and the status
+// of the context error if it ended before the validation completed.
+func validate(ctx context.Context, msg interface{}, opts []validator.Option) error {
+	err := validator.ValidateContext(ctx, msg, opts...)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
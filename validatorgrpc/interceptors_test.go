@@ -0,0 +1,100 @@
+// Copyright 2016 Michal Witkowski. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package validatorgrpc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	validator "github.com/monstrum/go-proto-validators"
+	"github.com/monstrum/go-proto-validators/validatorgrpc"
+)
+
+// request is valid when it has a name.
+type request struct {
+	name string
+}
+
+func (r *request) ValidateAllContext(ctx context.Context) error {
+	validations := validator.NewValidationErrors(ctx)
+	if r.name == "" {
+		validations.AddValidationError("Name", "string_not_empty", "value must not be an empty string")
+	}
+	return validations.Err()
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	observer := &validator.MemoryObserver{}
+	ctx := validator.WithObserver(context.Background(), observer)
+	interceptor := validatorgrpc.UnaryServerInterceptor()
+	handled := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled++
+		return req, nil
+	}
+
+	_, err := interceptor(ctx, &request{}, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 0, handled, "invalid requests don't reach the handler")
+	assert.Equal(t, 1, observer.Validations("*validatorgrpc_test.request"))
+	assert.Equal(t, "string_not_empty", observer.Violations()[0].Kind)
+
+	_, err = interceptor(ctx, &request{name: "alice"}, &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, 2, observer.Validations("*validatorgrpc_test.request"))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = interceptor(cancelled, &request{}, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+type recvStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []*request
+}
+
+func (s *recvStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recvStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+	*m.(*request) = *s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	observer := &validator.MemoryObserver{}
+	stream := &recvStream{
+		ctx:  validator.WithObserver(context.Background(), observer),
+		msgs: []*request{{name: "alice"}, {}},
+	}
+	var errs []error
+	err := validatorgrpc.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		for {
+			err := ss.RecvMsg(&request{})
+			if err == io.EOF {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+	})
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.Equal(t, codes.InvalidArgument, status.Code(errs[1]))
+	assert.Equal(t, 2, observer.Validations("*validatorgrpc_test.request"))
+}